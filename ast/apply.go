@@ -0,0 +1,269 @@
+package ast
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ApplyFunc is called by Apply for each node, with a Cursor on it. Its
+// result says whether to go on, see Apply.
+type ApplyFunc func(*Cursor) bool
+
+// Apply walks the tree at root in the order of Walk and lets pre and post
+// change it through the Cursor they get, modeled on astutil.Apply of the
+// Go tools.
+//
+// pre is called for a node before its children, and returning false skips
+// them and post for that node. post is called after the children, and
+// returning false stops Apply altogether. Either may be nil.
+//
+// Children are walked as they are after pre has run, so the node pre
+// replaced a node with is the one walked. Nodes inserted with
+// InsertBefore and InsertAfter are not walked. Apply returns root, or the
+// node it was replaced with.
+func Apply(root Node, pre, post ApplyFunc) (result Node) {
+	defer func() {
+		if r := recover(); r != nil && r != abortApply {
+			panic(r)
+		}
+		result = root
+	}()
+
+	a := &application{pre: pre, post: post}
+	a.apply(nil, "", &root, nil, root)
+	return root
+}
+
+var abortApply = new(int)
+
+// A Cursor is where Apply is in the tree: the node, its parent and the
+// field of the parent holding it.
+type Cursor struct {
+	parent Node
+	name   string
+	node   Node
+
+	// field is where the node is stored, unless it is in a list
+	field *Node
+	list  childList
+	iter  *iterator
+}
+
+// Node is the current node
+func (c *Cursor) Node() Node { return c.node }
+
+// Parent is the node holding the current node, nil for the root
+func (c *Cursor) Parent() Node { return c.parent }
+
+// Name is the name of the field of Parent holding the current node, like
+// "Body" or "Condition", "" for the root
+func (c *Cursor) Name() string { return c.name }
+
+// Index is the index of the current node in a list field like Body or
+// ElseIfs, or -1 if the field holds a single node
+func (c *Cursor) Index() int {
+	if c.list == nil {
+		return -1
+	}
+	return c.iter.index
+}
+
+// Replace puts node where the current node is. An element of ElseIfs must
+// be replaced by an *ElseIfStatement and one of Parameters by a
+// *Parameter, like the slices hold.
+func (c *Cursor) Replace(node Node) {
+	if c.list != nil {
+		c.list.set(c.iter.index, node)
+		c.refresh()
+		return
+	}
+	*c.field = node
+	c.node = node
+}
+
+// Delete removes the current node from the list it is in. Called from
+// pre, the children of the removed node are still walked and post still
+// called for it, as for any other node. It panics when the node is not in
+// a list.
+func (c *Cursor) Delete() {
+	c.mustBeInList("Delete")
+	c.node = c.list.delete(c.iter.index)
+	c.iter.step--
+}
+
+// InsertBefore puts node into the list the current node is in, before it.
+// It panics when the node is not in a list.
+func (c *Cursor) InsertBefore(node Node) {
+	c.mustBeInList("InsertBefore")
+	c.list.insert(c.iter.index, node)
+	c.iter.index++
+	c.refresh()
+}
+
+// InsertAfter puts node into the list the current node is in, after it.
+// It panics when the node is not in a list.
+func (c *Cursor) InsertAfter(node Node) {
+	c.mustBeInList("InsertAfter")
+	c.list.insert(c.iter.index+1, node)
+	c.iter.step++
+	c.refresh()
+}
+
+func (c *Cursor) mustBeInList(method string) {
+	if c.list == nil {
+		panic(fmt.Sprintf("Apply: %s of a node that is not in a list", method))
+	}
+}
+
+// refresh points the cursor at the node in its list again. ElseIfs and
+// Parameters hold copies, which move when the slice grows.
+func (c *Cursor) refresh() {
+	c.node = c.list.at(c.iter.index)
+}
+
+// iterator is the position in the list being walked, step is how far the
+// next node is, which Delete and InsertAfter change
+type iterator struct {
+	index, step int
+}
+
+// childList is a field holding several children, []Node or the slices of
+// nodes stored by value. delete returns the node it removed, for the
+// slices of values a copy, as the element it was is then the next one.
+type childList interface {
+	len() int
+	at(idx int) Node
+	set(idx int, node Node)
+	insert(idx int, node Node)
+	delete(idx int) Node
+}
+
+type nodeList struct{ nodes *[]Node }
+
+func (l nodeList) len() int                  { return len(*l.nodes) }
+func (l nodeList) at(idx int) Node           { return (*l.nodes)[idx] }
+func (l nodeList) set(idx int, node Node)    { (*l.nodes)[idx] = node }
+func (l nodeList) insert(idx int, node Node) { *l.nodes = slices.Insert(*l.nodes, idx, node) }
+func (l nodeList) delete(idx int) Node {
+	node := (*l.nodes)[idx]
+	*l.nodes = slices.Delete(*l.nodes, idx, idx+1)
+	return node
+}
+
+type elseIfList struct{ elseIfs *[]ElseIfStatement }
+
+func (l elseIfList) len() int               { return len(*l.elseIfs) }
+func (l elseIfList) at(idx int) Node        { return &(*l.elseIfs)[idx] }
+func (l elseIfList) set(idx int, node Node) { (*l.elseIfs)[idx] = *mustBe[*ElseIfStatement](node) }
+func (l elseIfList) insert(idx int, node Node) {
+	*l.elseIfs = slices.Insert(*l.elseIfs, idx, *mustBe[*ElseIfStatement](node))
+}
+func (l elseIfList) delete(idx int) Node {
+	elseIf := (*l.elseIfs)[idx]
+	*l.elseIfs = slices.Delete(*l.elseIfs, idx, idx+1)
+	return &elseIf
+}
+
+type parameterList struct{ params *[]Parameter }
+
+func (l parameterList) len() int               { return len(*l.params) }
+func (l parameterList) at(idx int) Node        { return &(*l.params)[idx] }
+func (l parameterList) set(idx int, node Node) { (*l.params)[idx] = *mustBe[*Parameter](node) }
+func (l parameterList) insert(idx int, node Node) {
+	*l.params = slices.Insert(*l.params, idx, *mustBe[*Parameter](node))
+}
+func (l parameterList) delete(idx int) Node {
+	param := (*l.params)[idx]
+	*l.params = slices.Delete(*l.params, idx, idx+1)
+	return &param
+}
+
+// mustBe is node as the pointer type a list of nodes stored by value needs
+func mustBe[T Node](node Node) T {
+	n, ok := node.(T)
+	if !ok {
+		var want T
+		panic(fmt.Sprintf("Apply: cannot store %T where a %T is held", node, want))
+	}
+	return n
+}
+
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+func (a *application) apply(parent Node, name string, field *Node, list childList, node Node) {
+	if node == nil {
+		return
+	}
+
+	saved := a.cursor
+	a.cursor = Cursor{parent: parent, name: name, node: node, field: field, list: list, iter: &a.iter}
+	defer func() { a.cursor = saved }()
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		return
+	}
+
+	switch n := a.cursor.node.(type) {
+	case nil:
+		// replaced by nil, nothing left to walk
+		return
+	case *Program:
+		a.applyList(n, "Body", nodeList{&n.Body})
+	case *Declaration:
+		a.apply(n, "Value", &n.Value, nil, n.Value)
+	case *Assignment:
+		a.apply(n, "Value", &n.Value, nil, n.Value)
+	case *Identifier, *Literal, *BreakStatement, *ContinueStatement:
+		// no children
+	case *TemplateLiteral:
+		a.applyList(n, "Expressions", nodeList{&n.Expressions})
+	case *BinaryExpression:
+		a.apply(n, "Left", &n.Left, nil, n.Left)
+		a.apply(n, "Right", &n.Right, nil, n.Right)
+	case *Parameter:
+		a.apply(n, "Default", &n.Default, nil, n.Default)
+	case *FunctionDeclaration:
+		a.applyList(n, "Parameters", parameterList{&n.Parameters})
+		a.applyList(n, "Body", nodeList{&n.Body})
+	case *FunctionCall:
+		a.applyList(n, "Arguments", nodeList{&n.Arguments})
+	case *IfStatement:
+		a.apply(n, "Condition", &n.Condition, nil, n.Condition)
+		a.applyList(n, "Consequent", nodeList{&n.Consequent})
+		a.applyList(n, "ElseIfs", elseIfList{&n.ElseIfs})
+		a.applyList(n, "Alternate", nodeList{&n.Alternate})
+	case *ElseIfStatement:
+		a.apply(n, "Condition", &n.Condition, nil, n.Condition)
+		a.applyList(n, "Consequent", nodeList{&n.Consequent})
+	case *WhileLoop:
+		a.apply(n, "Condition", &n.Condition, nil, n.Condition)
+		a.applyList(n, "Body", nodeList{&n.Body})
+	case *RepeatLoop:
+		a.applyList(n, "Body", nodeList{&n.Body})
+	case *ReturnStatement:
+		a.apply(n, "Value", &n.Value, nil, n.Value)
+	default:
+		panic(fmt.Sprintf("Apply: unexpected node type %T", n))
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(abortApply)
+	}
+}
+
+// applyList walks the nodes of a list field, which the cursor may change
+// as it goes
+func (a *application) applyList(parent Node, name string, list childList) {
+	saved := a.iter
+	a.iter.index = 0
+	for a.iter.index < list.len() {
+		a.iter.step = 1
+		a.apply(parent, name, nil, list, list.at(a.iter.index))
+		a.iter.index += a.iter.step
+	}
+	a.iter = saved
+}
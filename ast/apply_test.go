@@ -0,0 +1,352 @@
+package ast_test
+
+import (
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, src string) *ast.Program {
+	t.Helper()
+	program, err := parser.Parse(src)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", src, err)
+	}
+	return program
+}
+
+// label names a node in the visit logs of the tests
+func label(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.Identifier:
+		return n.Name
+	case *ast.FunctionCall:
+		return n.Name + "()"
+	case *ast.Parameter:
+		return "param " + n.Name
+	case *ast.ElseIfStatement:
+		return "ya fir " + label(n.Condition)
+	default:
+		return node.NodeType()
+	}
+}
+
+// names lists the labels of nodes
+func names[T ast.Node](nodes []T) string {
+	labels := make([]string, len(nodes))
+	for idx, node := range nodes {
+		labels[idx] = label(node)
+	}
+	return strings.Join(labels, ", ")
+}
+
+// ptrs turns a slice of nodes stored by value into pointers to them
+func ptrs[T any](values []T) []*T {
+	out := make([]*T, len(values))
+	for idx := range values {
+		out[idx] = &values[idx]
+	}
+	return out
+}
+
+// applyLog runs Apply with pre, logging the nodes pre and post see
+func applyLog(root ast.Node, pre ast.ApplyFunc) string {
+	var log []string
+	ast.Apply(root, func(c *ast.Cursor) bool {
+		log = append(log, label(c.Node()))
+		if pre != nil {
+			return pre(c)
+		}
+		return true
+	}, func(c *ast.Cursor) bool {
+		log = append(log, "/"+label(c.Node()))
+		return true
+	})
+	return strings.Join(log, " ")
+}
+
+// call reports whether c is on a call statement of the given name
+func call(c *ast.Cursor, name string) bool {
+	n, ok := c.Node().(*ast.FunctionCall)
+	return ok && n.Name == name && c.Name() == "Body"
+}
+
+func TestApplyBody(t *testing.T) {
+	tests := []struct {
+		name string
+		pre  ast.ApplyFunc
+		log  string
+		body string
+	}{
+		{
+			name: "delete",
+			pre: func(c *ast.Cursor) bool {
+				if call(c, "b") {
+					c.Delete()
+				}
+				return true
+			},
+			log:  "Program a() /a() b() x /x /b() c() /c() /Program",
+			body: "a(), c()",
+		},
+		{
+			name: "delete every statement",
+			pre: func(c *ast.Cursor) bool {
+				if c.Name() == "Body" {
+					c.Delete()
+				}
+				return true
+			},
+			log:  "Program a() /a() b() x /x /b() c() /c() /Program",
+			body: "",
+		},
+		{
+			name: "replace",
+			pre: func(c *ast.Cursor) bool {
+				if call(c, "b") {
+					c.Replace(&ast.FunctionCall{Name: "z", Arguments: []ast.Node{&ast.Identifier{Name: "y"}}})
+				}
+				return true
+			},
+			log:  "Program a() /a() b() y /y /z() c() /c() /Program",
+			body: "a(), z(), c()",
+		},
+		{
+			name: "insert before",
+			pre: func(c *ast.Cursor) bool {
+				if call(c, "b") {
+					c.InsertBefore(&ast.FunctionCall{Name: "before"})
+				}
+				return true
+			},
+			log:  "Program a() /a() b() x /x /b() c() /c() /Program",
+			body: "a(), before(), b(), c()",
+		},
+		{
+			name: "insert after",
+			pre: func(c *ast.Cursor) bool {
+				if call(c, "b") {
+					c.InsertAfter(&ast.FunctionCall{Name: "after"})
+				}
+				return true
+			},
+			log:  "Program a() /a() b() x /x /b() c() /c() /Program",
+			body: "a(), b(), after(), c()",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := parse(t, "a()\nb(x)\nc()\n")
+			if log := applyLog(program, tt.pre); log != tt.log {
+				t.Errorf("visited:\n got %s\nwant %s", log, tt.log)
+			}
+			if body := names(program.Body); body != tt.body {
+				t.Errorf("body is %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestApplyElseIfs(t *testing.T) {
+	const src = "agar a {b()} ya fir c {d()} ya fir e {f()}"
+	tests := []struct {
+		name    string
+		pre     ast.ApplyFunc
+		log     string
+		elseIfs string
+	}{
+		{
+			name: "delete",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.ElseIfStatement); ok && label(n.Condition) == "c" {
+					c.Delete()
+				}
+				return true
+			},
+			log:     "ya fir c c /c d() /d() /ya fir c ya fir e e /e f() /f() /ya fir e",
+			elseIfs: "ya fir e",
+		},
+		{
+			name: "delete both",
+			pre: func(c *ast.Cursor) bool {
+				if _, ok := c.Node().(*ast.ElseIfStatement); ok {
+					c.Delete()
+				}
+				return true
+			},
+			log:     "ya fir c c /c d() /d() /ya fir c ya fir e e /e f() /f() /ya fir e",
+			elseIfs: "",
+		},
+		{
+			name: "replace",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.ElseIfStatement); ok && label(n.Condition) == "c" {
+					c.Replace(&ast.ElseIfStatement{Condition: &ast.Identifier{Name: "z"}})
+				}
+				return true
+			},
+			log:     "ya fir c z /z /ya fir z ya fir e e /e f() /f() /ya fir e",
+			elseIfs: "ya fir z, ya fir e",
+		},
+		{
+			name: "insert before",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.ElseIfStatement); ok && label(n.Condition) == "e" {
+					c.InsertBefore(&ast.ElseIfStatement{Condition: &ast.Identifier{Name: "z"}})
+				}
+				return true
+			},
+			log:     "ya fir c c /c d() /d() /ya fir c ya fir e e /e f() /f() /ya fir e",
+			elseIfs: "ya fir c, ya fir z, ya fir e",
+		},
+		{
+			name: "insert after",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.ElseIfStatement); ok && label(n.Condition) == "c" {
+					c.InsertAfter(&ast.ElseIfStatement{Condition: &ast.Identifier{Name: "z"}})
+				}
+				return true
+			},
+			log:     "ya fir c c /c d() /d() /ya fir c ya fir e e /e f() /f() /ya fir e",
+			elseIfs: "ya fir c, ya fir z, ya fir e",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := parse(t, src)
+			ifStmt := program.Body[0].(*ast.IfStatement)
+			log := applyLog(ifStmt, tt.pre)
+			// leave out the condition and the blocks of agar itself
+			log = log[strings.Index(log, "ya fir"):strings.LastIndex(log, " /IfStatement")]
+			if log != tt.log {
+				t.Errorf("visited:\n got %s\nwant %s", log, tt.log)
+			}
+			if elseIfs := names(ptrs(ifStmt.ElseIfs)); elseIfs != tt.elseIfs {
+				t.Errorf("else ifs are %q, want %q", elseIfs, tt.elseIfs)
+			}
+		})
+	}
+}
+
+func TestApplyParameters(t *testing.T) {
+	const src = "firseKaro f(a, b = g(), c = h()) {}"
+	tests := []struct {
+		name   string
+		pre    ast.ApplyFunc
+		log    string
+		params string
+	}{
+		{
+			name: "delete",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.Parameter); ok && n.Name == "a" {
+					c.Delete()
+				}
+				return true
+			},
+			log:    "param a /param a param b g() /g() /param b param c h() /h() /param c",
+			params: "param b, param c",
+		},
+		{
+			name: "delete with a default",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.Parameter); ok && n.Name == "b" {
+					c.Delete()
+				}
+				return true
+			},
+			log:    "param a /param a param b g() /g() /param b param c h() /h() /param c",
+			params: "param a, param c",
+		},
+		{
+			name: "replace",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.Parameter); ok && n.Name == "b" {
+					c.Replace(&ast.Parameter{Name: "z", Default: &ast.FunctionCall{Name: "k"}})
+				}
+				return true
+			},
+			log:    "param a /param a param b k() /k() /param z param c h() /h() /param c",
+			params: "param a, param z, param c",
+		},
+		{
+			name: "insert before",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.Parameter); ok && n.Name == "a" {
+					c.InsertBefore(&ast.Parameter{Name: "z"})
+				}
+				return true
+			},
+			log:    "param a /param a param b g() /g() /param b param c h() /h() /param c",
+			params: "param z, param a, param b, param c",
+		},
+		{
+			name: "insert after",
+			pre: func(c *ast.Cursor) bool {
+				if n, ok := c.Node().(*ast.Parameter); ok && n.Name == "a" {
+					c.InsertAfter(&ast.Parameter{Name: "z", Default: &ast.FunctionCall{Name: "k"}})
+				}
+				return true
+			},
+			log:    "param a /param a param b g() /g() /param b param c h() /h() /param c",
+			params: "param a, param z, param b, param c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := parse(t, src)
+			fn := program.Body[0].(*ast.FunctionDeclaration)
+			log := applyLog(fn, tt.pre)
+			log = strings.TrimSuffix(strings.TrimPrefix(log, "FunctionDeclaration "), " /FunctionDeclaration")
+			if log != tt.log {
+				t.Errorf("visited:\n got %s\nwant %s", log, tt.log)
+			}
+			if params := names(ptrs(fn.Parameters)); params != tt.params {
+				t.Errorf("parameters are %q, want %q", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestApplyReplaceRoot(t *testing.T) {
+	program := parse(t, "a()")
+	root := ast.Apply(program.Body[0], func(c *ast.Cursor) bool {
+		if c.Parent() == nil {
+			c.Replace(&ast.Identifier{Name: "b"})
+		}
+		return true
+	}, nil)
+	if label(root) != "b" {
+		t.Errorf("Apply returned %s, want the identifier b", label(root))
+	}
+}
+
+func TestApplyStop(t *testing.T) {
+	program := parse(t, "a()\nb()\nc()\n")
+	var seen []string
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		seen = append(seen, label(c.Node()))
+		return true
+	}, func(c *ast.Cursor) bool {
+		return label(c.Node()) != "b()"
+	})
+	if got := strings.Join(seen, " "); got != "Program a() b()" {
+		t.Errorf("visited %s before stopping, want Program a() b()", got)
+	}
+}
+
+func TestApplyNotInList(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Delete of a node outside a list did not panic")
+		}
+	}()
+	program := parse(t, "ye a = b")
+	ast.Apply(program, func(c *ast.Cursor) bool {
+		if c.Name() == "Value" {
+			c.Delete()
+		}
+		return true
+	}, nil)
+}
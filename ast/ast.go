@@ -0,0 +1,223 @@
+// Package ast declares the types of the syntax tree the parser builds
+// and the interpreter runs, and tools to walk, rewrite and encode it.
+package ast
+
+import (
+	"github.com/suraj-9849/hindiLang.git/token"
+	"strconv"
+)
+
+// Node is a node of the syntax tree. NodeType is the name of its Go type,
+// Position where it is in the source.
+type Node interface {
+	NodeType() string
+	Position() token.Position
+}
+
+// Program is a whole source file, the root of every tree the parser
+// builds. It always starts at line 1, column 1.
+type Program struct {
+	Body []Node `json:"body"`
+}
+
+func (p *Program) NodeType() string         { return "Program" }
+func (p *Program) Position() token.Position { return token.Position{Line: 1, Column: 1} }
+
+// Declaration is "ye naam = value", Type is set when it is written
+// "ye naam: type = value"
+type Declaration struct {
+	Name  string         `json:"name"`
+	Type  string         `json:"type"`
+	Value Node           `json:"value"`
+	Doc   string         `json:"doc"`
+	Pos   token.Position `json:"pos"`
+}
+
+func (d *Declaration) NodeType() string         { return "Declaration" }
+func (d *Declaration) Position() token.Position { return d.Pos }
+
+// Assignment is "naam = value" to a variable declared before
+type Assignment struct {
+	Name  string         `json:"name"`
+	Value Node           `json:"value"`
+	Pos   token.Position `json:"pos"`
+}
+
+func (a *Assignment) NodeType() string         { return "Assignment" }
+func (a *Assignment) Position() token.Position { return a.Pos }
+
+// Identifier is the name of a variable or function used as a value
+type Identifier struct {
+	Name string         `json:"name"`
+	Pos  token.Position `json:"pos"`
+}
+
+func (i *Identifier) NodeType() string         { return "Identifier" }
+func (i *Identifier) Position() token.Position { return i.Pos }
+
+// Literal is a number or a string. Kind is the type of the token it was
+// written as, token.Number or token.String, and Value its text, for a
+// string with the escapes resolved.
+type Literal struct {
+	Kind  string         `json:"kind"`
+	Value string         `json:"value"`
+	Pos   token.Position `json:"pos"`
+}
+
+func (l *Literal) NodeType() string         { return "Literal" }
+func (l *Literal) Position() token.Position { return l.Pos }
+
+// IsNumber reports whether l is a number. A Literal built without a Kind
+// is one when its Value parses as a number.
+func (l *Literal) IsNumber() bool {
+	if l.Kind != "" {
+		return l.Kind == token.Number
+	}
+	_, err := strconv.ParseFloat(l.Value, 64)
+	return err == nil
+}
+
+// TemplateLiteral is an interpolated string like "Namaste {naam}".
+// Quasis holds the text around the placeholders, so it always has one
+// more element than Expressions.
+type TemplateLiteral struct {
+	Quasis      []string       `json:"quasis"`
+	Expressions []Node         `json:"expressions"`
+	Pos         token.Position `json:"pos"`
+}
+
+func (t *TemplateLiteral) NodeType() string         { return "TemplateLiteral" }
+func (t *TemplateLiteral) Position() token.Position { return t.Pos }
+
+// BinaryExpression is positioned at its operator
+type BinaryExpression struct {
+	Operator string         `json:"operator"`
+	Left     Node           `json:"left"`
+	Right    Node           `json:"right"`
+	Pos      token.Position `json:"pos"`
+}
+
+func (b *BinaryExpression) NodeType() string         { return "BinaryExpression" }
+func (b *BinaryExpression) Position() token.Position { return b.Pos }
+
+// Parameter is one parameter of a function. Default is the value it gets
+// when a call leaves it out, nil if it must be passed. A Rest parameter
+// comes last and collects the remaining arguments into a list. Type is
+// the optional annotation, for a Rest parameter it is the type of each
+// argument it collects.
+type Parameter struct {
+	Name    string         `json:"name"`
+	Type    string         `json:"type"`
+	Default Node           `json:"default"`
+	Rest    bool           `json:"rest"`
+	Pos     token.Position `json:"pos"`
+}
+
+func (p *Parameter) NodeType() string         { return "Parameter" }
+func (p *Parameter) Position() token.Position { return p.Pos }
+
+// FunctionDeclaration is "firseKaro naam(params): type { body }",
+// ReturnType is "" when there is no annotation
+type FunctionDeclaration struct {
+	Name       string         `json:"name"`
+	Parameters []Parameter    `json:"parameters"`
+	ReturnType string         `json:"returnType"`
+	Body       []Node         `json:"body"`
+	Doc        string         `json:"doc"`
+	Pos        token.Position `json:"pos"`
+}
+
+func (f *FunctionDeclaration) NodeType() string         { return "FunctionDeclaration" }
+func (f *FunctionDeclaration) Position() token.Position { return f.Pos }
+
+// FunctionCall is "naam(arguments)", the function is always named
+type FunctionCall struct {
+	Name      string         `json:"name"`
+	Arguments []Node         `json:"arguments"`
+	Pos       token.Position `json:"pos"`
+}
+
+func (f *FunctionCall) NodeType() string         { return "FunctionCall" }
+func (f *FunctionCall) Position() token.Position { return f.Pos }
+
+// IfStatement is "agar condition { }" with its "ya fir" branches in
+// ElseIfs and the block after "ya" in Alternate, empty when there is none
+type IfStatement struct {
+	Condition  Node              `json:"condition"`
+	Consequent []Node            `json:"consequent"`
+	ElseIfs    []ElseIfStatement `json:"elseIfs"`
+	Alternate  []Node            `json:"alternate"`
+	Pos        token.Position    `json:"pos"`
+}
+
+func (i *IfStatement) NodeType() string         { return "IfStatement" }
+func (i *IfStatement) Position() token.Position { return i.Pos }
+
+// ElseIfStatement is one "ya fir condition { }" branch of an IfStatement
+type ElseIfStatement struct {
+	Condition  Node           `json:"condition"`
+	Consequent []Node         `json:"consequent"`
+	Pos        token.Position `json:"pos"`
+}
+
+func (e *ElseIfStatement) NodeType() string         { return "ElseIfStatement" }
+func (e *ElseIfStatement) Position() token.Position { return e.Pos }
+
+// WhileLoop is "jabtak condition { body }"
+type WhileLoop struct {
+	Condition Node           `json:"condition"`
+	Body      []Node         `json:"body"`
+	Pos       token.Position `json:"pos"`
+}
+
+func (w *WhileLoop) NodeType() string         { return "WhileLoop" }
+func (w *WhileLoop) Position() token.Position { return w.Pos }
+
+// RepeatLoop is "dohraye { body }", which runs until roko or wapas bhejo
+type RepeatLoop struct {
+	Body []Node         `json:"body"`
+	Pos  token.Position `json:"pos"`
+}
+
+func (r *RepeatLoop) NodeType() string         { return "RepeatLoop" }
+func (r *RepeatLoop) Position() token.Position { return r.Pos }
+
+// BreakStatement is roko, leaving the innermost loop
+type BreakStatement struct {
+	Pos token.Position `json:"pos"`
+}
+
+func (b *BreakStatement) NodeType() string         { return "BreakStatement" }
+func (b *BreakStatement) Position() token.Position { return b.Pos }
+
+// ContinueStatement is aage badho, going on with the next iteration of the
+// innermost loop
+type ContinueStatement struct {
+	Pos token.Position `json:"pos"`
+}
+
+func (c *ContinueStatement) NodeType() string         { return "ContinueStatement" }
+func (c *ContinueStatement) Position() token.Position { return c.Pos }
+
+// ReturnStatement is "wapas bhejo value", Value is nil for a bare wapas
+// bhejo
+type ReturnStatement struct {
+	Value Node           `json:"value"`
+	Pos   token.Position `json:"pos"`
+}
+
+func (r *ReturnStatement) NodeType() string         { return "ReturnStatement" }
+func (r *ReturnStatement) Position() token.Position { return r.Pos }
+
+// TypeNames are the types annotations can name, the values of
+// interp.RuntimeValue.Type() plus "any"
+var TypeNames = []string{"number", "string", "bool", "list", "record", "function", "null", "any"}
+
+// Start is where the first token of node is. It is the node's Position
+// except for a BinaryExpression, which is positioned at its operator.
+func Start(node Node) token.Position {
+	if binary, ok := node.(*BinaryExpression); ok {
+		return Start(binary.Left)
+	}
+	return node.Position()
+}
@@ -0,0 +1,427 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/token"
+)
+
+// Nodes are written as JSON objects with a "nodeType" key holding their
+// NodeType() next to their fields, named like the Go fields with a lower
+// case first letter. Positions are {"line": 1, "column": 1} under "pos".
+// UnmarshalNode reads them back, so json.Marshal and UnmarshalNode
+// round-trip a program:
+//
+//	{"nodeType": "Declaration", "name": "x", "type": "", "doc": "",
+//	 "value": {"nodeType": "Literal", "kind": "NUMBER", "value": "1",
+//	 "pos": {...}},
+//	 "pos": {"line": 1, "column": 1}}
+//
+// Each MarshalJSON converts its node to a type of the same fields, which
+// has no methods, so marshalNode doesn't call it again.
+
+func (p *Program) MarshalJSON() ([]byte, error) {
+	type fields Program
+	// a Program has no Pos field, it always starts at 1:1
+	return marshalNode(p.NodeType(), &struct {
+		*fields
+		Pos token.Position `json:"pos"`
+	}{(*fields)(p), p.Position()})
+}
+
+func (d *Declaration) MarshalJSON() ([]byte, error) {
+	type fields Declaration
+	return marshalNode(d.NodeType(), (*fields)(d))
+}
+
+func (a *Assignment) MarshalJSON() ([]byte, error) {
+	type fields Assignment
+	return marshalNode(a.NodeType(), (*fields)(a))
+}
+
+func (i *Identifier) MarshalJSON() ([]byte, error) {
+	type fields Identifier
+	return marshalNode(i.NodeType(), (*fields)(i))
+}
+
+func (l *Literal) MarshalJSON() ([]byte, error) {
+	type fields Literal
+	return marshalNode(l.NodeType(), (*fields)(l))
+}
+
+func (t *TemplateLiteral) MarshalJSON() ([]byte, error) {
+	type fields TemplateLiteral
+	return marshalNode(t.NodeType(), (*fields)(t))
+}
+
+func (b *BinaryExpression) MarshalJSON() ([]byte, error) {
+	type fields BinaryExpression
+	return marshalNode(b.NodeType(), (*fields)(b))
+}
+
+func (p *Parameter) MarshalJSON() ([]byte, error) {
+	type fields Parameter
+	return marshalNode(p.NodeType(), (*fields)(p))
+}
+
+func (f *FunctionDeclaration) MarshalJSON() ([]byte, error) {
+	type fields FunctionDeclaration
+	return marshalNode(f.NodeType(), (*fields)(f))
+}
+
+func (f *FunctionCall) MarshalJSON() ([]byte, error) {
+	type fields FunctionCall
+	return marshalNode(f.NodeType(), (*fields)(f))
+}
+
+func (i *IfStatement) MarshalJSON() ([]byte, error) {
+	type fields IfStatement
+	return marshalNode(i.NodeType(), (*fields)(i))
+}
+
+func (e *ElseIfStatement) MarshalJSON() ([]byte, error) {
+	type fields ElseIfStatement
+	return marshalNode(e.NodeType(), (*fields)(e))
+}
+
+func (w *WhileLoop) MarshalJSON() ([]byte, error) {
+	type fields WhileLoop
+	return marshalNode(w.NodeType(), (*fields)(w))
+}
+
+func (r *RepeatLoop) MarshalJSON() ([]byte, error) {
+	type fields RepeatLoop
+	return marshalNode(r.NodeType(), (*fields)(r))
+}
+
+func (b *BreakStatement) MarshalJSON() ([]byte, error) {
+	type fields BreakStatement
+	return marshalNode(b.NodeType(), (*fields)(b))
+}
+
+func (c *ContinueStatement) MarshalJSON() ([]byte, error) {
+	type fields ContinueStatement
+	return marshalNode(c.NodeType(), (*fields)(c))
+}
+
+func (r *ReturnStatement) MarshalJSON() ([]byte, error) {
+	type fields ReturnStatement
+	return marshalNode(r.NodeType(), (*fields)(r))
+}
+
+// marshalNode writes the fields of a node as a JSON object with the
+// "nodeType" key first. It doesn't escape <, > and &, which are common
+// operators.
+func marshalNode[T any](nodeType string, fields *T) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(nodeType); err != nil {
+		return nil, err
+	}
+	head := append([]byte(`{"nodeType":`), bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...)
+
+	buf.Reset()
+	if err := enc.Encode(fields); err != nil {
+		return nil, err
+	}
+	object := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	if len(object) == 2 {
+		return append(head, '}'), nil
+	}
+	return append(append(head, ','), object[1:]...), nil
+}
+
+// UnmarshalNode reads a node written by json.Marshal, the "nodeType" key
+// picks its Go type. JSON null is a nil Node.
+func UnmarshalNode(data []byte) (Node, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var head struct {
+		NodeType string `json:"nodeType"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	var node Node
+	switch head.NodeType {
+	case "Program":
+		node = &Program{}
+	case "Declaration":
+		node = &Declaration{}
+	case "Assignment":
+		node = &Assignment{}
+	case "Identifier":
+		node = &Identifier{}
+	case "Literal":
+		node = &Literal{}
+	case "TemplateLiteral":
+		node = &TemplateLiteral{}
+	case "BinaryExpression":
+		node = &BinaryExpression{}
+	case "Parameter":
+		node = &Parameter{}
+	case "FunctionDeclaration":
+		node = &FunctionDeclaration{}
+	case "FunctionCall":
+		node = &FunctionCall{}
+	case "IfStatement":
+		node = &IfStatement{}
+	case "ElseIfStatement":
+		node = &ElseIfStatement{}
+	case "WhileLoop":
+		node = &WhileLoop{}
+	case "RepeatLoop":
+		node = &RepeatLoop{}
+	case "BreakStatement":
+		node = &BreakStatement{}
+	case "ContinueStatement":
+		node = &ContinueStatement{}
+	case "ReturnStatement":
+		node = &ReturnStatement{}
+	default:
+		return nil, fmt.Errorf("unknown node type %q", head.NodeType)
+	}
+	if err := json.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// unmarshalNodes reads a list of nodes, keeping null apart from []
+func unmarshalNodes(raw []json.RawMessage) ([]Node, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	nodes := make([]Node, len(raw))
+	for idx, data := range raw {
+		node, err := UnmarshalNode(data)
+		if err != nil {
+			return nil, err
+		}
+		nodes[idx] = node
+	}
+	return nodes, nil
+}
+
+// The nodes holding other nodes need an UnmarshalJSON, the fields of type
+// Node are read as raw JSON first, hiding the ones of the node's own type,
+// and then turned into nodes by UnmarshalNode.
+
+func (p *Program) UnmarshalJSON(data []byte) error {
+	type fields Program
+	var raw struct {
+		*fields
+		Body []json.RawMessage `json:"body"`
+	}
+	raw.fields = (*fields)(p)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	p.Body, err = unmarshalNodes(raw.Body)
+	return err
+}
+
+func (d *Declaration) UnmarshalJSON(data []byte) error {
+	type fields Declaration
+	var raw struct {
+		*fields
+		Value json.RawMessage `json:"value"`
+	}
+	raw.fields = (*fields)(d)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	d.Value, err = UnmarshalNode(raw.Value)
+	return err
+}
+
+func (a *Assignment) UnmarshalJSON(data []byte) error {
+	type fields Assignment
+	var raw struct {
+		*fields
+		Value json.RawMessage `json:"value"`
+	}
+	raw.fields = (*fields)(a)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	a.Value, err = UnmarshalNode(raw.Value)
+	return err
+}
+
+func (t *TemplateLiteral) UnmarshalJSON(data []byte) error {
+	type fields TemplateLiteral
+	var raw struct {
+		*fields
+		Expressions []json.RawMessage `json:"expressions"`
+	}
+	raw.fields = (*fields)(t)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	t.Expressions, err = unmarshalNodes(raw.Expressions)
+	return err
+}
+
+func (b *BinaryExpression) UnmarshalJSON(data []byte) error {
+	type fields BinaryExpression
+	var raw struct {
+		*fields
+		Left  json.RawMessage `json:"left"`
+		Right json.RawMessage `json:"right"`
+	}
+	raw.fields = (*fields)(b)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if b.Left, err = UnmarshalNode(raw.Left); err != nil {
+		return err
+	}
+	b.Right, err = UnmarshalNode(raw.Right)
+	return err
+}
+
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type fields Parameter
+	var raw struct {
+		*fields
+		Default json.RawMessage `json:"default"`
+	}
+	raw.fields = (*fields)(p)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	p.Default, err = UnmarshalNode(raw.Default)
+	return err
+}
+
+func (f *FunctionDeclaration) UnmarshalJSON(data []byte) error {
+	type fields FunctionDeclaration
+	var raw struct {
+		*fields
+		Body []json.RawMessage `json:"body"`
+	}
+	raw.fields = (*fields)(f)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	f.Body, err = unmarshalNodes(raw.Body)
+	return err
+}
+
+func (f *FunctionCall) UnmarshalJSON(data []byte) error {
+	type fields FunctionCall
+	var raw struct {
+		*fields
+		Arguments []json.RawMessage `json:"arguments"`
+	}
+	raw.fields = (*fields)(f)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	f.Arguments, err = unmarshalNodes(raw.Arguments)
+	return err
+}
+
+func (i *IfStatement) UnmarshalJSON(data []byte) error {
+	type fields IfStatement
+	var raw struct {
+		*fields
+		Condition  json.RawMessage   `json:"condition"`
+		Consequent []json.RawMessage `json:"consequent"`
+		Alternate  []json.RawMessage `json:"alternate"`
+	}
+	raw.fields = (*fields)(i)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if i.Condition, err = UnmarshalNode(raw.Condition); err != nil {
+		return err
+	}
+	if i.Consequent, err = unmarshalNodes(raw.Consequent); err != nil {
+		return err
+	}
+	i.Alternate, err = unmarshalNodes(raw.Alternate)
+	return err
+}
+
+func (e *ElseIfStatement) UnmarshalJSON(data []byte) error {
+	type fields ElseIfStatement
+	var raw struct {
+		*fields
+		Condition  json.RawMessage   `json:"condition"`
+		Consequent []json.RawMessage `json:"consequent"`
+	}
+	raw.fields = (*fields)(e)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if e.Condition, err = UnmarshalNode(raw.Condition); err != nil {
+		return err
+	}
+	e.Consequent, err = unmarshalNodes(raw.Consequent)
+	return err
+}
+
+func (w *WhileLoop) UnmarshalJSON(data []byte) error {
+	type fields WhileLoop
+	var raw struct {
+		*fields
+		Condition json.RawMessage   `json:"condition"`
+		Body      []json.RawMessage `json:"body"`
+	}
+	raw.fields = (*fields)(w)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	if w.Condition, err = UnmarshalNode(raw.Condition); err != nil {
+		return err
+	}
+	w.Body, err = unmarshalNodes(raw.Body)
+	return err
+}
+
+func (r *RepeatLoop) UnmarshalJSON(data []byte) error {
+	type fields RepeatLoop
+	var raw struct {
+		*fields
+		Body []json.RawMessage `json:"body"`
+	}
+	raw.fields = (*fields)(r)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	r.Body, err = unmarshalNodes(raw.Body)
+	return err
+}
+
+func (r *ReturnStatement) UnmarshalJSON(data []byte) error {
+	type fields ReturnStatement
+	var raw struct {
+		*fields
+		Value json.RawMessage `json:"value"`
+	}
+	raw.fields = (*fields)(r)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var err error
+	r.Value, err = UnmarshalNode(raw.Value)
+	return err
+}
@@ -0,0 +1,145 @@
+package ast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// everyNode is a program with every type of node in it
+const everyNode = `/// doc
+ye a: number = 1 + 2 * (3 - 4)
+ye s = "<b> & {a}"
+a = a % 2
+firseKaro f(x, y: string = "y", ...rest): number {
+	agar x < 1 && y != "" {
+		wapas bhejo
+	} ya fir x >= 2 || y == "z" {
+		roko
+	} ya {
+		aage badho
+	}
+	jabtak x <= 3 { x = x + 1 }
+	dohraye { roko }
+	wapas bhejo f(x, "a")
+}
+`
+
+func TestJSONRoundTrip(t *testing.T) {
+	sources := map[string]string{"every node": everyNode}
+	examples, err := filepath.Glob(filepath.Join("..", "examples", "*.hlang"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, example := range examples {
+		src, err := os.ReadFile(example)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources[filepath.Base(example)] = string(src)
+	}
+
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			program := parse(t, src)
+			data, err := json.Marshal(program)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := ast.UnmarshalNode(data)
+			if err != nil {
+				t.Fatalf("reading back %s: %v", data, err)
+			}
+			if !reflect.DeepEqual(back, program) {
+				again, _ := json.Marshal(back)
+				t.Errorf("round trip changed the program:\n%s\nto\n%s", data, again)
+			}
+		})
+	}
+}
+
+func TestJSONEveryNodeHasPos(t *testing.T) {
+	data, err := json.Marshal(parse(t, everyNode))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	var check func(v any)
+	check = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if nodeType, ok := v["nodeType"].(string); ok {
+				seen[nodeType] = true
+				if _, ok := v["pos"].(map[string]any); !ok {
+					t.Errorf("%s has no pos", nodeType)
+				}
+			}
+			for _, child := range v {
+				check(child)
+			}
+		case []any:
+			for _, child := range v {
+				check(child)
+			}
+		}
+	}
+	check(tree)
+
+	for _, nodeType := range []string{"Program", "Declaration", "Assignment", "Identifier", "Literal",
+		"TemplateLiteral", "BinaryExpression", "Parameter", "FunctionDeclaration", "FunctionCall",
+		"IfStatement", "ElseIfStatement", "WhileLoop", "RepeatLoop", "BreakStatement",
+		"ContinueStatement", "ReturnStatement"} {
+		if !seen[nodeType] {
+			t.Errorf("no %s in the test program", nodeType)
+		}
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	// encoded like hlang ast, which doesn't escape HTML
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(parse(t, `ye a = "<1>" + 1`)); err != nil {
+		t.Fatal(err)
+	}
+	data := strings.TrimSuffix(buf.String(), "\n")
+	want := `{"nodeType":"Program","body":[{"nodeType":"Declaration","name":"a","type":"","value":` +
+		`{"nodeType":"BinaryExpression","operator":"+",` +
+		`"left":{"nodeType":"Literal","kind":"STRING","value":"<1>","pos":{"line":1,"column":8}},` +
+		`"right":{"nodeType":"Literal","kind":"NUMBER","value":"1","pos":{"line":1,"column":16}},` +
+		`"pos":{"line":1,"column":14}},"doc":"","pos":{"line":1,"column":1}}],"pos":{"line":1,"column":1}}`
+	if data != want {
+		t.Errorf("got\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestUnmarshalNodeErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{`{"nodeType": "Nope"}`, `unknown node type "Nope"`},
+		{`{"nodeType": "Program", "body": [{"nodeType": "Nope"}]}`, `unknown node type "Nope"`},
+		{`[1]`, "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		_, err := ast.UnmarshalNode([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("UnmarshalNode(%s): error = %v, want one holding %q", tt.data, err, tt.err)
+		}
+	}
+	if node, err := ast.UnmarshalNode([]byte("null")); node != nil || err != nil {
+		t.Errorf("UnmarshalNode(null) = %v, %v, want nil", node, err)
+	}
+}
@@ -0,0 +1,105 @@
+package ast
+
+import "fmt"
+
+// A Visitor's Visit is called by Walk for each node it finds. If the
+// Visitor w it returns is not nil, Walk visits each child of the node with
+// w and then calls w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk goes through the tree at node depth first, like go/ast.Walk: it
+// calls v.Visit(node) and, unless that returns nil, walks each child of
+// node with the visitor returned. Children are visited in source order,
+// nil children like the value of a bare wapas bhejo are skipped.
+//
+// The ElseIfs of an IfStatement and the Parameters of a
+// FunctionDeclaration are stored by value, Walk passes pointers to the
+// elements of those slices so changes to them are kept.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkList(v, n.Body)
+	case *Declaration:
+		walkIfSet(v, n.Value)
+	case *Assignment:
+		walkIfSet(v, n.Value)
+	case *Identifier, *Literal, *BreakStatement, *ContinueStatement:
+		// no children
+	case *TemplateLiteral:
+		walkList(v, n.Expressions)
+	case *BinaryExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+	case *Parameter:
+		walkIfSet(v, n.Default)
+	case *FunctionDeclaration:
+		for idx := range n.Parameters {
+			Walk(v, &n.Parameters[idx])
+		}
+		walkList(v, n.Body)
+	case *FunctionCall:
+		walkList(v, n.Arguments)
+	case *IfStatement:
+		Walk(v, n.Condition)
+		walkList(v, n.Consequent)
+		for idx := range n.ElseIfs {
+			Walk(v, &n.ElseIfs[idx])
+		}
+		walkList(v, n.Alternate)
+	case *ElseIfStatement:
+		Walk(v, n.Condition)
+		walkList(v, n.Consequent)
+	case *WhileLoop:
+		Walk(v, n.Condition)
+		walkList(v, n.Body)
+	case *RepeatLoop:
+		walkList(v, n.Body)
+	case *ReturnStatement:
+		walkIfSet(v, n.Value)
+	default:
+		panic(fmt.Sprintf("Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkList(v Visitor, list []Node) {
+	for _, node := range list {
+		Walk(v, node)
+	}
+}
+
+func walkIfSet(v Visitor, node Node) {
+	if node != nil {
+		Walk(v, node)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect walks the tree at node like Walk, calling f(node) for each node
+// and f(nil) after its children. The children of a node are skipped when
+// f returns false for it.
+//
+//	ast.Inspect(program, func(node ast.Node) bool {
+//		if call, ok := node.(*ast.FunctionCall); ok {
+//			fmt.Println(call.Pos, call.Name)
+//		}
+//		return true
+//	})
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
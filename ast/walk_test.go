@@ -0,0 +1,203 @@
+package ast_test
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"strings"
+	"testing"
+)
+
+// visit labels a node in the visit trees of the tests, with enough of
+// its fields to find it in the source
+func visit(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.Literal:
+		return fmt.Sprintf("Literal %q", n.Value)
+	case *ast.BinaryExpression:
+		return "BinaryExpression " + n.Operator
+	case *ast.Declaration:
+		return "Declaration " + n.Name
+	case *ast.Assignment:
+		return "Assignment " + n.Name
+	case *ast.FunctionDeclaration:
+		return "FunctionDeclaration " + n.Name
+	case *ast.Parameter:
+		return "Parameter " + n.Name
+	case *ast.Identifier, *ast.FunctionCall:
+		return node.NodeType() + " " + label(node)
+	default:
+		return node.NodeType()
+	}
+}
+
+// inspectTree runs Inspect on root and writes the nodes it visits one per
+// line, indented by how many nodes they are inside of. The nil Inspect
+// passes after the children of a node ends its indentation.
+func inspectTree(root ast.Node, f func(ast.Node) bool) string {
+	var sb strings.Builder
+	depth := 0
+	ast.Inspect(root, func(node ast.Node) bool {
+		if node == nil {
+			depth--
+			return true
+		}
+		fmt.Fprintf(&sb, "%s%s\n", strings.Repeat("  ", depth), visit(node))
+		if f != nil && !f(node) {
+			return false
+		}
+		depth++
+		return true
+	})
+	if depth != 0 {
+		fmt.Fprintf(&sb, "unbalanced, %d nodes not ended\n", depth)
+	}
+	return sb.String()
+}
+
+func TestInspectEveryNode(t *testing.T) {
+	want := `Program
+  Declaration a
+    BinaryExpression +
+      Literal "1"
+      BinaryExpression *
+        Literal "2"
+        BinaryExpression -
+          Literal "3"
+          Literal "4"
+  Declaration s
+    TemplateLiteral
+      Identifier a
+  Assignment a
+    BinaryExpression %
+      Identifier a
+      Literal "2"
+  FunctionDeclaration f
+    Parameter x
+    Parameter y
+      Literal "y"
+    Parameter rest
+    IfStatement
+      BinaryExpression &&
+        BinaryExpression <
+          Identifier x
+          Literal "1"
+        BinaryExpression !=
+          Identifier y
+          Literal ""
+      ReturnStatement
+      ElseIfStatement
+        BinaryExpression ||
+          BinaryExpression >=
+            Identifier x
+            Literal "2"
+          BinaryExpression ==
+            Identifier y
+            Literal "z"
+        BreakStatement
+      ContinueStatement
+    WhileLoop
+      BinaryExpression <=
+        Identifier x
+        Literal "3"
+      Assignment x
+        BinaryExpression +
+          Identifier x
+          Literal "1"
+    RepeatLoop
+      BreakStatement
+    ReturnStatement
+      FunctionCall f()
+        Identifier x
+        Literal "a"
+`
+	if got := inspectTree(parse(t, everyNode), nil); got != want {
+		t.Errorf("Inspect visited:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInspectSkip(t *testing.T) {
+	program := parse(t, `firseKaro f(a = 1) { bol(a) }
+agar x { y() } ya fir z { w() }
+bol(2)`)
+	want := `Program
+  FunctionDeclaration f
+  IfStatement
+    Identifier x
+    FunctionCall y()
+    ElseIfStatement
+  FunctionCall bol()
+    Literal "2"
+`
+	got := inspectTree(program, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FunctionDeclaration, *ast.ElseIfStatement:
+			return false
+		}
+		return true
+	})
+	if got != want {
+		t.Errorf("Inspect visited:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestInspectSlicePointers(t *testing.T) {
+	program := parse(t, `firseKaro f(a, b = 1) { bol(a, b) }
+agar x { y() } ya fir z { w() }`)
+	// Parameters and ElseIfs are stored by value, changes made through
+	// the nodes Inspect gives must still land in the tree
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.Parameter:
+			n.Name = strings.ToUpper(n.Name)
+		case *ast.ElseIfStatement:
+			n.Condition = &ast.Identifier{Name: "naya"}
+		}
+		return true
+	})
+
+	fn := program.Body[0].(*ast.FunctionDeclaration)
+	if got := names(ptrs(fn.Parameters)); got != "param A, param B" {
+		t.Errorf("parameters = %s, want param A, param B", got)
+	}
+	ifStmt := program.Body[1].(*ast.IfStatement)
+	if got := names(ptrs(ifStmt.ElseIfs)); got != "ya fir naya" {
+		t.Errorf("else ifs = %s, want ya fir naya", got)
+	}
+}
+
+// depthVisitor gives the children of a node a visitor one level deeper,
+// logging the visitor each node and each nil is passed to
+type depthVisitor struct {
+	depth int
+	log   *[]string
+}
+
+func (v depthVisitor) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		*v.log = append(*v.log, fmt.Sprintf("%d:end", v.depth))
+		return nil
+	}
+	*v.log = append(*v.log, fmt.Sprintf("%d:%s", v.depth, visit(node)))
+	return depthVisitor{v.depth + 1, v.log}
+}
+
+func TestWalkVisitors(t *testing.T) {
+	var log []string
+	ast.Walk(depthVisitor{0, &log}, parse(t, `ye a = b + 1
+wapas bhejo`))
+	// the nil after the children goes to the visitor they were walked with
+	want := []string{
+		"0:Program",
+		"1:Declaration a",
+		"2:BinaryExpression +",
+		"3:Identifier b", "4:end",
+		`3:Literal "1"`, "4:end",
+		"3:end",
+		"2:end",
+		"1:ReturnStatement", "2:end",
+		"1:end",
+	}
+	if strings.Join(log, " ") != strings.Join(want, " ") {
+		t.Errorf("Walk visited:\n%s\nwant:\n%s", strings.Join(log, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,316 @@
+// Package check finds likely mistakes in HindiScript programs without
+// running them, the way hlang check does.
+package check
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/interp"
+	"github.com/suraj-9849/hindiLang.git/token"
+	"io"
+	"sort"
+)
+
+// Problem is something Program or Types found wrong with a program. Rule
+// names the kind of problem, e.g. "unused-variable", so tools can filter
+// on it.
+type Problem struct {
+	Pos     token.Position
+	Rule    string
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Pos, p.Message)
+}
+
+// Program looks for mistakes in a program without running it:
+//
+//   - undefined: a name that is never declared
+//   - used-before-declaration: a variable read before its ye ran
+//   - unused-variable, unused-function: declared but never used
+//   - arity: a call with a different number of arguments than the
+//     function has parameters
+//   - unreachable: statements after wapas bhejo, roko or aage badho
+//   - loop-control: roko or aage badho outside of a loop
+//   - constant-condition: an agar, ya fir or jabtak condition that is
+//     always true
+//
+// The options are the ones the program will be run with, so functions
+// added with interp.WithFunc are known to Program too. Problems are sorted by
+// position.
+func Program(program *ast.Program, opts ...interp.Option) []Problem {
+	interpreter := interp.NewInterpreter(append([]interp.Option{interp.WithStdout(io.Discard), interp.WithStderr(io.Discard)}, opts...)...)
+	c := &checker{interpreter: interpreter}
+
+	builtins := newScope(nil)
+	globals := interpreter.Globals()
+	for _, name := range globals.Names() {
+		val, _ := globals.Get(name)
+		sym := &symbol{kind: "builtin", declared: true, most: -1}
+		if native, ok := val.(*interp.NativeFunction); ok && native.Arity >= 0 {
+			sym.least, sym.most = native.Arity, native.Arity
+		}
+		builtins.symbols[name] = sym
+	}
+
+	c.function(newScope(builtins), program.Body, nil)
+
+	sort.SliceStable(c.problems, func(a, b int) bool {
+		return c.problems[a].Pos.Before(c.problems[b].Pos)
+	})
+	return c.problems
+}
+
+type checker struct {
+	interpreter *interp.Interpreter
+	problems    []Problem
+}
+
+// scope holds the names of one function body or of the top level, blocks
+// share the scope they are in like they do when running
+type scope struct {
+	parent  *scope
+	symbols map[string]*symbol
+	names   []string
+}
+
+type symbol struct {
+	kind     string // "builtin", "parameter", "variable" or "function"
+	pos      token.Position
+	declared bool
+	used     bool
+
+	// least and most are how many arguments a function takes, see
+	// interp.FunctionValue.Arity
+	least, most int
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, symbols: map[string]*symbol{}}
+}
+
+func (s *scope) define(name, kind string, pos token.Position) *symbol {
+	if sym, ok := s.symbols[name]; ok {
+		// a name declared twice can't be relied on to hold one function
+		if sym.kind != "parameter" && (sym.kind != kind || kind == "function") {
+			sym.kind = "variable"
+		}
+		return sym
+	}
+	sym := &symbol{kind: kind, pos: pos}
+	s.symbols[name] = sym
+	s.names = append(s.names, name)
+	return sym
+}
+
+func (c *checker) report(pos token.Position, rule, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{Pos: pos, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// function checks a function body, or the top level of the program, in
+// a scope of its own
+func (c *checker) function(s *scope, body []ast.Node, params []ast.Parameter) {
+	for _, param := range params {
+		s.define(param.Name, "parameter", param.Pos)
+	}
+	// defaults may use the parameters before them
+	for _, param := range params {
+		if param.Default != nil {
+			c.expr(s, param.Default)
+		}
+		s.symbols[param.Name].declared = true
+	}
+	c.declare(s, body)
+	c.block(s, body, 0)
+
+	for _, name := range s.names {
+		sym := s.symbols[name]
+		if sym.used {
+			continue
+		}
+		switch sym.kind {
+		case "variable":
+			c.report(sym.pos, "unused-variable", "%s is declared but never used", name)
+		case "function":
+			c.report(sym.pos, "unused-function", "function %s is never called", name)
+		}
+	}
+}
+
+// declare adds the names a body declares to its scope up front, so a
+// function can call one declared after it
+func (c *checker) declare(s *scope, body []ast.Node) {
+	for _, node := range body {
+		switch n := node.(type) {
+		case *ast.Declaration:
+			s.define(n.Name, "variable", n.Pos)
+		case *ast.FunctionDeclaration:
+			sym := s.define(n.Name, "function", n.Pos)
+			sym.least, sym.most = (&interp.FunctionValue{Parameters: n.Parameters}).Arity()
+		case *ast.Assignment:
+			if sym, ok := s.symbols[n.Name]; ok && sym.kind == "function" {
+				sym.kind = "variable"
+			}
+		case *ast.IfStatement:
+			c.declare(s, n.Consequent)
+			for _, elseIf := range n.ElseIfs {
+				c.declare(s, elseIf.Consequent)
+			}
+			c.declare(s, n.Alternate)
+		case *ast.WhileLoop:
+			c.declare(s, n.Body)
+		case *ast.RepeatLoop:
+			c.declare(s, n.Body)
+		}
+	}
+}
+
+// block checks statements in order, loops is how many loops they are in.
+// Statements after wapas bhejo, roko or aage badho are reported once and
+// still checked for other problems.
+func (c *checker) block(s *scope, body []ast.Node, loops int) {
+	var stopped ast.Node
+	reported := false
+	for _, node := range body {
+		if stopped != nil && !reported {
+			c.report(ast.Start(node), "unreachable", "unreachable code after %s", keywordOf(stopped))
+			reported = true
+		}
+		c.statement(s, node, loops)
+		switch node.(type) {
+		case *ast.ReturnStatement, *ast.BreakStatement, *ast.ContinueStatement:
+			if stopped == nil {
+				stopped = node
+			}
+		}
+	}
+}
+
+func keywordOf(node ast.Node) string {
+	switch node.(type) {
+	case *ast.BreakStatement:
+		return "roko"
+	case *ast.ContinueStatement:
+		return "aage badho"
+	default:
+		return "wapas bhejo"
+	}
+}
+
+func (c *checker) statement(s *scope, node ast.Node, loops int) {
+	switch n := node.(type) {
+	case *ast.Declaration:
+		c.expr(s, n.Value)
+		s.symbols[n.Name].declared = true
+	case *ast.Assignment:
+		c.expr(s, n.Value)
+		if sym, owner := s.lookup(n.Name); sym == nil {
+			c.report(n.Pos, "undefined", "assignment to undeclared variable %s", n.Name)
+		} else if owner == s && !sym.declared {
+			c.report(n.Pos, "used-before-declaration", "%s is assigned before it is declared", n.Name)
+		}
+	case *ast.FunctionDeclaration:
+		s.symbols[n.Name].declared = true
+		c.function(newScope(s), n.Body, n.Parameters)
+	case *ast.IfStatement:
+		c.condition(s, n.Condition)
+		c.block(s, n.Consequent, loops)
+		for _, elseIf := range n.ElseIfs {
+			c.condition(s, elseIf.Condition)
+			c.block(s, elseIf.Consequent, loops)
+		}
+		c.block(s, n.Alternate, loops)
+	case *ast.WhileLoop:
+		c.condition(s, n.Condition)
+		c.block(s, n.Body, loops+1)
+	case *ast.RepeatLoop:
+		c.block(s, n.Body, loops+1)
+	case *ast.BreakStatement:
+		if loops == 0 {
+			c.report(n.Pos, "loop-control", "roko outside of a loop")
+		}
+	case *ast.ContinueStatement:
+		if loops == 0 {
+			c.report(n.Pos, "loop-control", "aage badho outside of a loop")
+		}
+	case *ast.ReturnStatement:
+		if n.Value != nil {
+			c.expr(s, n.Value)
+		}
+	default:
+		c.expr(s, node)
+	}
+}
+
+func (c *checker) condition(s *scope, node ast.Node) {
+	c.expr(s, node)
+	if !isConstant(node) {
+		return
+	}
+	if val, err := c.interpreter.Evaluate(node); err == nil && c.interpreter.Truthy(val) {
+		c.report(ast.Start(node), "constant-condition", "condition is always true")
+	}
+}
+
+// isConstant reports whether an expression is made of literals only
+func isConstant(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.Literal:
+		return true
+	case *ast.TemplateLiteral:
+		return len(n.Expressions) == 0
+	case *ast.BinaryExpression:
+		return isConstant(n.Left) && isConstant(n.Right)
+	default:
+		return false
+	}
+}
+
+func (c *checker) expr(s *scope, node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Identifier:
+		c.use(s, n.Name, n.Pos)
+	case *ast.FunctionCall:
+		sym := c.use(s, n.Name, n.Pos)
+		if sym != nil && (sym.kind == "function" || sym.kind == "builtin") &&
+			(len(n.Arguments) < sym.least || (sym.most >= 0 && len(n.Arguments) > sym.most)) {
+			c.report(n.Pos, "arity", "%s takes %s argument(s) but is called with %d", n.Name, interp.ArityRange(sym.least, sym.most), len(n.Arguments))
+		}
+		for _, arg := range n.Arguments {
+			c.expr(s, arg)
+		}
+	case *ast.TemplateLiteral:
+		for _, expr := range n.Expressions {
+			c.expr(s, expr)
+		}
+	case *ast.BinaryExpression:
+		c.expr(s, n.Left)
+		c.expr(s, n.Right)
+	}
+}
+
+// use marks a name as read, reporting it when it can't be found
+func (c *checker) use(s *scope, name string, pos token.Position) *symbol {
+	sym, owner := s.lookup(name)
+	if sym == nil {
+		c.report(pos, "undefined", "undefined: %s", name)
+		return nil
+	}
+	// names from enclosing scopes are declared by the time a function
+	// runs, in its own scope they have to come first
+	if owner == s && !sym.declared {
+		c.report(pos, "used-before-declaration", "%s is used before it is declared", name)
+	}
+	sym.used = true
+	return sym
+}
+
+func (s *scope) lookup(name string) (*symbol, *scope) {
+	for ; s != nil; s = s.parent {
+		if sym, ok := s.symbols[name]; ok {
+			return sym, s
+		}
+	}
+	return nil, nil
+}
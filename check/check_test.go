@@ -0,0 +1,201 @@
+package check
+
+import (
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/interp"
+	"testing"
+)
+
+func TestProgramUndefined(t *testing.T) {
+	testChecks(t, Program, []checkTest{
+		{name: "read", src: `bol(x)`,
+			want: []string{"1:5: undefined: x (undefined)"}},
+		{name: "call", src: `f()`,
+			want: []string{"1:1: undefined: f (undefined)"}},
+		{name: "assignment", src: `x = 1`,
+			want: []string{"1:1: assignment to undeclared variable x (undefined)"}},
+		{name: "in a template", src: `bol("a {y}")`,
+			want: []string{"1:9: undefined: y (undefined)"}},
+		{name: "parameter of another function", src: `firseKaro f(a) { bol(a) }
+firseKaro g() { bol(a) }
+f(1)
+g()`,
+			want: []string{"2:21: undefined: a (undefined)"}},
+		{name: "builtin", src: `bol(lambai("abc"))`},
+	})
+}
+
+func TestProgramUsedBeforeDeclaration(t *testing.T) {
+	testChecks(t, Program, []checkTest{
+		{name: "read", src: `bol(x)
+ye x = 1`,
+			want: []string{"1:5: x is used before it is declared (used-before-declaration)"}},
+		{name: "assignment", src: `x = 2
+ye x = 1
+bol(x)`,
+			want: []string{"1:1: x is assigned before it is declared (used-before-declaration)"}},
+		{name: "in its own value", src: `ye x = x + 1`,
+			want: []string{"1:8: x is used before it is declared (used-before-declaration)"}},
+		{name: "function called before it is declared", src: `f()
+firseKaro f() { bol(1) }`,
+			want: []string{"1:1: f is used before it is declared (used-before-declaration)"}},
+		{name: "function calling one declared after it", src: `firseKaro f() { g() }
+firseKaro g() { bol(1) }
+f()`},
+		{name: "global read by a function", src: `firseKaro f() { bol(x) }
+ye x = 1
+f()`},
+		{name: "default using a later parameter", src: `firseKaro f(a = b, b = 1) { bol(a, b) }
+f()`,
+			want: []string{"1:17: b is used before it is declared (used-before-declaration)"}},
+		{name: "default using an earlier parameter", src: `firseKaro f(a, b = a) { bol(a, b) }
+f(1)`},
+	})
+}
+
+func TestProgramUnused(t *testing.T) {
+	testChecks(t, Program, []checkTest{
+		{name: "variable", src: `ye x = 1`,
+			want: []string{"1:1: x is declared but never used (unused-variable)"}},
+		{name: "variable only assigned", src: `ye x = 1
+x = 2`,
+			want: []string{"1:1: x is declared but never used (unused-variable)"}},
+		{name: "variable in a function", src: `firseKaro f() { ye y = 1 }
+f()`,
+			want: []string{"1:17: y is declared but never used (unused-variable)"}},
+		{name: "function", src: `firseKaro f() { bol(1) }`,
+			want: []string{"1:1: function f is never called (unused-function)"}},
+		{name: "function used as a value", src: `firseKaro f() { bol(1) }
+bol(f)`},
+		{name: "unused parameter", src: `firseKaro f(a) { bol(1) }
+f(1)`},
+	})
+}
+
+func TestProgramArity(t *testing.T) {
+	testChecks(t, Program, []checkTest{
+		{name: "too few", src: `firseKaro f(a, b) { bol(a, b) }
+f(1)`,
+			want: []string{"2:1: f takes 2 argument(s) but is called with 1 (arity)"}},
+		{name: "too many", src: `firseKaro f(a) { bol(a) }
+f(1, 2)`,
+			want: []string{"2:1: f takes 1 argument(s) but is called with 2 (arity)"}},
+		{name: "defaults", src: `firseKaro f(a, b = 2) { bol(a, b) }
+f()
+f(1)
+f(1, 2)
+f(1, 2, 3)`,
+			want: []string{
+				"2:1: f takes 1 to 2 argument(s) but is called with 0 (arity)",
+				"5:1: f takes 1 to 2 argument(s) but is called with 3 (arity)",
+			}},
+		{name: "rest", src: `firseKaro f(a, ...baaki) { bol(a, baaki) }
+f()
+f(1, 2, 3, 4)`,
+			want: []string{"2:1: f takes at least 1 argument(s) but is called with 0 (arity)"}},
+		{name: "builtin", src: `bol(lambai("a", "b"))`,
+			want: []string{"1:5: lambai takes 1 argument(s) but is called with 2 (arity)"}},
+		{name: "variadic builtin", src: `bol(1, 2, 3)`},
+		{name: "function declared twice", src: `firseKaro f(a) { bol(a) }
+firseKaro f(a, b) { bol(a, b) }
+f(1)`},
+		{name: "function reassigned", src: `firseKaro f(a) { bol(a) }
+f = 1
+f(1, 2)`},
+	})
+}
+
+func TestProgramArityWithFunc(t *testing.T) {
+	opt := interp.WithFunc("jodo", 2, func(args []interp.RuntimeValue) (interp.RuntimeValue, error) {
+		return &interp.NullValue{}, nil
+	})
+	check := func(program *ast.Program, opts ...interp.Option) []Problem {
+		return Program(program, append(opts, opt)...)
+	}
+	testChecks(t, check, []checkTest{
+		{name: "right", src: `bol(jodo(1, 2))`},
+		{name: "wrong", src: `bol(jodo(1))`,
+			want: []string{"1:5: jodo takes 2 argument(s) but is called with 1 (arity)"}},
+	})
+}
+
+func TestProgramUnreachable(t *testing.T) {
+	testChecks(t, Program, []checkTest{
+		{name: "after wapas bhejo", src: `firseKaro f() {
+	wapas bhejo 1
+	bol(2)
+	bol(3)
+}
+f()`,
+			want: []string{"3:2: unreachable code after wapas bhejo (unreachable)"}},
+		{name: "after roko", src: `dohraye {
+	roko
+	bol(1)
+}`,
+			want: []string{"3:2: unreachable code after roko (unreachable)"}},
+		{name: "after aage badho", src: `ye i = 0
+jabtak i < 3 {
+	i = i + 1
+	aage badho
+	bol(i)
+}`,
+			want: []string{"5:2: unreachable code after aage badho (unreachable)"}},
+		{name: "still checked", src: `firseKaro f() {
+	wapas bhejo
+	bol(x)
+}
+f()`,
+			want: []string{
+				"3:2: unreachable code after wapas bhejo (unreachable)",
+				"3:6: undefined: x (undefined)",
+			}},
+		{name: "inside agar", src: `firseKaro f(a) {
+	agar a { wapas bhejo 1 }
+	wapas bhejo 2
+}
+f(1)`},
+	})
+}
+
+func TestProgramLoopControl(t *testing.T) {
+	testChecks(t, Program, []checkTest{
+		{name: "roko", src: `roko`,
+			want: []string{"1:1: roko outside of a loop (loop-control)"}},
+		{name: "aage badho", src: `aage badho`,
+			want: []string{"1:1: aage badho outside of a loop (loop-control)"}},
+		{name: "in agar", src: `ye a = 1
+agar a { roko }`,
+			want: []string{"2:10: roko outside of a loop (loop-control)"}},
+		{name: "in a function in a loop", src: `dohraye {
+	firseKaro f() { roko }
+	f()
+	roko
+}`,
+			want: []string{"2:18: roko outside of a loop (loop-control)"}},
+		{name: "in a loop", src: `ye a = 1
+jabtak a < 3 {
+	a = a + 1
+	agar a == 2 { aage badho }
+	roko
+}`},
+	})
+}
+
+func TestProgramConstantCondition(t *testing.T) {
+	testChecks(t, Program, []checkTest{
+		{name: "agar", src: `agar 1 { bol(1) }`,
+			want: []string{"1:6: condition is always true (constant-condition)"}},
+		{name: "ya fir", src: `ye a = 1
+agar a { bol(1) } ya fir "x" { bol(2) }`,
+			want: []string{"2:26: condition is always true (constant-condition)"}},
+		{name: "jabtak", src: `jabtak 1 < 2 { roko }`,
+			want: []string{"1:8: condition is always true (constant-condition)"}},
+		{name: "always false", src: `agar 0 { bol(1) }`},
+		{name: "not constant", src: `ye a = 1
+agar a < 2 { bol(a) }`},
+		{name: "string", src: `agar "x" { bol(1) }`,
+			want: []string{"1:6: condition is always true (constant-condition)"}},
+		{name: "template", src: `ye a = 1
+agar "{a}" { bol(a) }`},
+	})
+}
@@ -0,0 +1,428 @@
+package check
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/interp"
+	"github.com/suraj-9849/hindiLang.git/token"
+	"io"
+	"sort"
+)
+
+// builtinResults are the types builtins return, the ones left out can
+// return more than one type
+var builtinResults = map[string]string{
+	"bol":     "null",
+	"sankhya": "number",
+	"lambai":  "number",
+	"bahar":   "null",
+}
+
+// Types works out the types of a program's variables and functions
+// without running it and reports, with the rule "type", the places where
+// a value can't have the type it needs:
+//
+//   - arguments, defaults and return values that don't match the
+//     annotations of a function
+//   - ye and assignments that don't match the annotation of a variable
+//   - functions with a return type that can end without wapas bhejo
+//   - arithmetic and comparisons on values that are not numbers
+//   - calls of values that are not functions
+//
+// Names without an annotation get the type of all values assigned to
+// them, and parameters the type of all arguments passed, when those
+// agree. Anything it can't be sure of counts as "any" and is never
+// reported, so annotating more of a program makes the check stricter.
+func Types(program *ast.Program, opts ...interp.Option) []Problem {
+	interpreter := interp.NewInterpreter(append([]interp.Option{interp.WithStdout(io.Discard), interp.WithStderr(io.Discard)}, opts...)...)
+	tc := &typeChecker{funcs: map[*ast.FunctionDeclaration]*funcInfo{}}
+
+	builtins := newTypeScope(nil)
+	globals := interpreter.Globals()
+	for _, name := range globals.Names() {
+		val, _ := globals.Get(name)
+		v := &typeVar{inferred: val.Type(), result: "any"}
+		if result, ok := builtinResults[name]; ok {
+			v.result = result
+		}
+		builtins.vars[name] = v
+	}
+	top := newTypeScope(builtins)
+	tc.declare(top, program.Body)
+
+	// types only ever widen, so walking the program again until nothing
+	// changes settles them, then one more walk reports
+	for pass := 0; pass < 100; pass++ {
+		tc.changed = false
+		tc.block(top, program.Body, nil)
+		if !tc.changed {
+			break
+		}
+	}
+	tc.reporting = true
+	tc.block(top, program.Body, nil)
+
+	sort.SliceStable(tc.problems, func(a, b int) bool {
+		return tc.problems[a].Pos.Before(tc.problems[b].Pos)
+	})
+	return tc.problems
+}
+
+type typeChecker struct {
+	funcs     map[*ast.FunctionDeclaration]*funcInfo
+	problems  []Problem
+	changed   bool
+	reporting bool
+}
+
+type typeScope struct {
+	parent *typeScope
+	vars   map[string]*typeVar
+}
+
+// typeVar is what is known about a name. declared is its annotation and
+// inferred the type of the values assigned to it so far, "" before any.
+type typeVar struct {
+	declared string
+	inferred string
+
+	// fn is set while the name holds one known function, result is what
+	// calling a builtin returns
+	fn     *funcInfo
+	result string
+}
+
+type funcInfo struct {
+	decl    *ast.FunctionDeclaration
+	scope   *typeScope
+	returns string
+}
+
+func newTypeScope(parent *typeScope) *typeScope {
+	return &typeScope{parent: parent, vars: map[string]*typeVar{}}
+}
+
+func (s *typeScope) lookup(name string) *typeVar {
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// joinTypes is the type of a value that is either a or b
+func joinTypes(a, b string) string {
+	switch {
+	case a == "" || a == b:
+		return b
+	case b == "":
+		return a
+	default:
+		return "any"
+	}
+}
+
+func (v *typeVar) typ() string {
+	switch {
+	case v.declared != "":
+		return v.declared
+	case v.fn != nil:
+		return "function"
+	case v.inferred == "":
+		return "any"
+	default:
+		return v.inferred
+	}
+}
+
+func (tc *typeChecker) assign(v *typeVar, typ string) {
+	if v.declared != "" {
+		return
+	}
+	if joined := joinTypes(v.inferred, typ); joined != v.inferred {
+		v.inferred = joined
+		tc.changed = true
+	}
+}
+
+func (tc *typeChecker) report(pos token.Position, format string, args ...interface{}) {
+	if tc.reporting {
+		tc.problems = append(tc.problems, Problem{Pos: pos, Rule: "type", Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// mismatch reports whether a value of type got can't be stored where want
+// is required
+func mismatch(got, want string) bool {
+	return got != "any" && want != "" && want != "any" && got != want
+}
+
+// declare creates the variables a body declares, like checker.declare
+func (tc *typeChecker) declare(s *typeScope, body []ast.Node) {
+	for _, node := range body {
+		switch n := node.(type) {
+		case *ast.Declaration:
+			if v, ok := s.vars[n.Name]; ok {
+				if v.fn != nil {
+					v.fn, v.inferred = nil, "function"
+				}
+				continue
+			}
+			s.vars[n.Name] = &typeVar{declared: n.Type}
+		case *ast.FunctionDeclaration:
+			info := &funcInfo{decl: n, scope: newTypeScope(s)}
+			tc.funcs[n] = info
+			for _, param := range n.Parameters {
+				pv := &typeVar{declared: param.Type}
+				if param.Rest {
+					pv.declared = "list"
+				}
+				info.scope.vars[param.Name] = pv
+			}
+			tc.declare(info.scope, n.Body)
+
+			if v, ok := s.vars[n.Name]; ok {
+				v.fn, v.inferred = nil, joinTypes(v.inferred, "function")
+				continue
+			}
+			s.vars[n.Name] = &typeVar{fn: info}
+		case *ast.IfStatement:
+			tc.declare(s, n.Consequent)
+			for _, elseIf := range n.ElseIfs {
+				tc.declare(s, elseIf.Consequent)
+			}
+			tc.declare(s, n.Alternate)
+		case *ast.WhileLoop:
+			tc.declare(s, n.Body)
+		case *ast.RepeatLoop:
+			tc.declare(s, n.Body)
+		}
+	}
+}
+
+func (tc *typeChecker) block(s *typeScope, body []ast.Node, fn *funcInfo) {
+	for _, node := range body {
+		tc.statement(s, node, fn)
+	}
+}
+
+func (tc *typeChecker) statement(s *typeScope, node ast.Node, fn *funcInfo) {
+	switch n := node.(type) {
+	case *ast.Declaration:
+		typ := tc.expr(s, n.Value)
+		v := s.vars[n.Name]
+		if n.Type != "" {
+			if mismatch(typ, n.Type) {
+				tc.report(ast.Start(n.Value), "%s is declared as %s, it cannot hold a %s", n.Name, n.Type, typ)
+			}
+		} else {
+			tc.assign(v, typ)
+		}
+	case *ast.Assignment:
+		typ := tc.expr(s, n.Value)
+		v := s.lookup(n.Name)
+		if v == nil {
+			return
+		}
+		if v.fn != nil {
+			v.fn, v.inferred = nil, "function"
+			tc.changed = true
+		}
+		if mismatch(typ, v.declared) {
+			tc.report(ast.Start(n.Value), "%s is declared as %s, it cannot hold a %s", n.Name, v.declared, typ)
+		}
+		tc.assign(v, typ)
+	case *ast.FunctionDeclaration:
+		tc.function(tc.funcs[n])
+	case *ast.IfStatement:
+		tc.expr(s, n.Condition)
+		tc.block(s, n.Consequent, fn)
+		for _, elseIf := range n.ElseIfs {
+			tc.expr(s, elseIf.Condition)
+			tc.block(s, elseIf.Consequent, fn)
+		}
+		tc.block(s, n.Alternate, fn)
+	case *ast.WhileLoop:
+		tc.expr(s, n.Condition)
+		tc.block(s, n.Body, fn)
+	case *ast.RepeatLoop:
+		tc.block(s, n.Body, fn)
+	case *ast.ReturnStatement:
+		typ := "null"
+		if n.Value != nil {
+			typ = tc.expr(s, n.Value)
+		}
+		if fn != nil {
+			tc.returns(fn, typ, n.Pos)
+		}
+	case *ast.BreakStatement, *ast.ContinueStatement:
+	default:
+		tc.expr(s, node)
+	}
+}
+
+func (tc *typeChecker) function(fn *funcInfo) {
+	decl := fn.decl
+	for _, param := range decl.Parameters {
+		if param.Default == nil {
+			continue
+		}
+		typ := tc.expr(fn.scope, param.Default)
+		if mismatch(typ, param.Type) {
+			tc.report(ast.Start(param.Default), "default of %s must be a %s, got %s", param.Name, param.Type, typ)
+		}
+		tc.assign(fn.scope.vars[param.Name], typ)
+	}
+
+	tc.block(fn.scope, decl.Body, fn)
+
+	if !alwaysReturns(decl.Body) {
+		if decl.ReturnType != "" && mismatch("null", decl.ReturnType) {
+			tc.report(decl.Pos, "%s must return a %s but can end without wapas bhejo", decl.Name, decl.ReturnType)
+		}
+		tc.returns(fn, "null", token.Position{})
+	}
+}
+
+// returns records that fn returns a value of type typ
+func (tc *typeChecker) returns(fn *funcInfo, typ string, pos token.Position) {
+	if want := fn.decl.ReturnType; want != "" {
+		if pos != (token.Position{}) && mismatch(typ, want) {
+			tc.report(pos, "%s must return a %s, got %s", fn.decl.Name, want, typ)
+		}
+		return
+	}
+	if joined := joinTypes(fn.returns, typ); joined != fn.returns {
+		fn.returns = joined
+		tc.changed = true
+	}
+}
+
+// alwaysReturns reports whether a body can only end with wapas bhejo
+func alwaysReturns(body []ast.Node) bool {
+	if len(body) == 0 {
+		return false
+	}
+	switch n := body[len(body)-1].(type) {
+	case *ast.ReturnStatement:
+		return true
+	case *ast.IfStatement:
+		if len(n.Alternate) == 0 || !alwaysReturns(n.Consequent) || !alwaysReturns(n.Alternate) {
+			return false
+		}
+		for _, elseIf := range n.ElseIfs {
+			if !alwaysReturns(elseIf.Consequent) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (tc *typeChecker) expr(s *typeScope, node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.Literal:
+		if n.IsNumber() {
+			return "number"
+		}
+		return "string"
+	case *ast.TemplateLiteral:
+		for _, expr := range n.Expressions {
+			tc.expr(s, expr)
+		}
+		return "string"
+	case *ast.Identifier:
+		v := s.lookup(n.Name)
+		if v == nil {
+			return "any"
+		}
+		// a function used as a value can be called with anything
+		if v.fn != nil {
+			for _, param := range v.fn.decl.Parameters {
+				tc.assign(v.fn.scope.vars[param.Name], "any")
+			}
+		}
+		return v.typ()
+	case *ast.FunctionCall:
+		return tc.call(s, n)
+	case *ast.BinaryExpression:
+		left, right := tc.expr(s, n.Left), tc.expr(s, n.Right)
+		switch n.Operator {
+		case "+":
+			if left == "number" && right == "number" {
+				return "number"
+			}
+			// anything else is joined as text
+			if (left != "any" && left != "number") || (right != "any" && right != "number") {
+				return "string"
+			}
+			return "any"
+		case "-", "*", "/", "%":
+			tc.needNumbers(n, left, right)
+			return "number"
+		case "<", ">", "<=", ">=", "==", "!=":
+			tc.needNumbers(n, left, right)
+			return "bool"
+		}
+	}
+	return "any"
+}
+
+func (tc *typeChecker) needNumbers(n *ast.BinaryExpression, left, right string) {
+	for _, typ := range []string{left, right} {
+		if mismatch(typ, "number") {
+			tc.report(n.Pos, "operator %s needs numbers, got %s", n.Operator, typ)
+			return
+		}
+	}
+}
+
+func (tc *typeChecker) call(s *typeScope, n *ast.FunctionCall) string {
+	args := make([]string, len(n.Arguments))
+	for idx, arg := range n.Arguments {
+		args[idx] = tc.expr(s, arg)
+	}
+
+	v := s.lookup(n.Name)
+	if v == nil {
+		return "any"
+	}
+	if v.fn == nil {
+		if typ := v.typ(); typ != "any" && typ != "function" {
+			tc.report(n.Pos, "%s is a %s, not a function", n.Name, typ)
+			return "any"
+		}
+		if v.result == "" {
+			return "any"
+		}
+		return v.result
+	}
+
+	decl := v.fn.decl
+	for idx, typ := range args {
+		if len(decl.Parameters) == 0 {
+			break
+		}
+		param := decl.Parameters[min(idx, len(decl.Parameters)-1)]
+		if idx >= len(decl.Parameters) && !param.Rest {
+			break
+		}
+		if mismatch(typ, param.Type) {
+			tc.report(ast.Start(n.Arguments[idx]), "argument %d of %s must be a %s, got %s", idx+1, n.Name, param.Type, typ)
+		}
+		if !param.Rest {
+			tc.assign(v.fn.scope.vars[param.Name], typ)
+		}
+	}
+
+	if decl.ReturnType != "" {
+		return decl.ReturnType
+	}
+	if v.fn.returns == "" {
+		return "any"
+	}
+	return v.fn.returns
+}
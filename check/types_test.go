@@ -0,0 +1,108 @@
+package check
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/interp"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"strings"
+	"testing"
+)
+
+// problems parses src and returns the problems check finds in it, one
+// "line:column: message (rule)" string each
+func problems(t *testing.T, check func(*ast.Program, ...interp.Option) []Problem, src string) []string {
+	t.Helper()
+	program, err := parser.Parse(src)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", src, err)
+	}
+	var found []string
+	for _, p := range check(program) {
+		found = append(found, fmt.Sprintf("%d:%d: %s (%s)", p.Pos.Line, p.Pos.Column, p.Message, p.Rule))
+	}
+	return found
+}
+
+type checkTest struct {
+	name string
+	src  string
+	want []string
+}
+
+func testChecks(t *testing.T, check func(*ast.Program, ...interp.Option) []Problem, tests []checkTest) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := problems(t, check, tt.src)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestTypesLiterals(t *testing.T) {
+	testChecks(t, Types, []checkTest{
+		{name: "numeric string declared string", src: `ye s: string = "123"`},
+		{name: "numeric string argument", src: `firseKaro f(a: string) { bol(a) }
+f("42")`},
+		{name: "numeric string declared number", src: `ye n: number = "5"`,
+			want: []string{"1:16: n is declared as number, it cannot hold a string (type)"}},
+		{name: "numeric string argument for number", src: `firseKaro g(a: number) { bol(a) }
+g("42")`,
+			want: []string{"2:3: argument 1 of g must be a number, got string (type)"}},
+	})
+}
+
+func TestTypes(t *testing.T) {
+	testChecks(t, Types, []checkTest{
+		{name: "declaration", src: `ye s: string = 1`,
+			want: []string{"1:16: s is declared as string, it cannot hold a number (type)"}},
+		{name: "assignment", src: `ye n: number = 1
+n = "x"`,
+			want: []string{"2:5: n is declared as number, it cannot hold a string (type)"}},
+		{name: "inferred declaration", src: `ye a = 1
+ye s: string = a`,
+			want: []string{"2:16: s is declared as string, it cannot hold a number (type)"}},
+		{name: "any", src: `ye a = 1
+a = "x"
+ye s: string = a
+ye n: number = a`},
+		{name: "argument", src: `firseKaro f(a: number) { bol(a) }
+f("x")`,
+			want: []string{"2:3: argument 1 of f must be a number, got string (type)"}},
+		{name: "rest argument", src: `firseKaro f(a, ...baaki: number) { bol(a) }
+f("x", 1, "y")`,
+			want: []string{"2:11: argument 3 of f must be a number, got string (type)"}},
+		{name: "default", src: `firseKaro f(a: number = "x") { bol(a) }
+f()`,
+			want: []string{"1:25: default of a must be a number, got string (type)"}},
+		{name: "return", src: `firseKaro f(): number { wapas bhejo "x" }
+f()`,
+			want: []string{"1:25: f must return a number, got string (type)"}},
+		{name: "inferred return", src: `firseKaro f() { wapas bhejo "x" }
+ye n: number = f()`,
+			want: []string{"2:16: n is declared as number, it cannot hold a string (type)"}},
+		{name: "missing return", src: `firseKaro f(a): number {
+	agar a { wapas bhejo 1 }
+}
+f(1)`,
+			want: []string{"1:1: f must return a number but can end without wapas bhejo (type)"}},
+		{name: "return on every branch", src: `firseKaro f(a): number {
+	agar a { wapas bhejo 1 } ya fir a < 2 { wapas bhejo 2 } ya { wapas bhejo 3 }
+}
+f(1)`},
+		{name: "operator", src: `ye n = "a" - 1`,
+			want: []string{"1:12: operator - needs numbers, got string (type)"}},
+		{name: "comparison", src: `ye b = 1 < "a"`,
+			want: []string{"1:10: operator < needs numbers, got string (type)"}},
+		{name: "joining text", src: `ye s: string = "a" + 1`},
+		{name: "not a function", src: `ye n = 1
+n()`,
+			want: []string{"2:1: n is a number, not a function (type)"}},
+		{name: "parameter used as any", src: `firseKaro f(a) { wapas bhejo a - 1 }
+f("x")`,
+			want: []string{"1:32: operator - needs numbers, got string (type)"}},
+	})
+}
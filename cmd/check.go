@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/check"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"os"
+	"sort"
+)
+
+// checkProblem is how a problem is written with --json
+type checkProblem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// checkCommand reports problems in the given files without running them.
+// It exits with 1 when there are any.
+func checkCommand(args []string) {
+	const usage = "./hlang.exe check [--json] [--types] <files...>"
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the problems as a JSON array")
+	types := flags.Bool("types", false, "also check the types of values, see the type annotations")
+	parseFlags(flags, usage, args)
+
+	if flags.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Usage Error: Please provide the .hlang files to check")
+		fmt.Fprintln(os.Stderr, "Usage: "+usage)
+		os.Exit(exitUsage)
+	}
+
+	code := 0
+	problems := []checkProblem{}
+	for _, filename := range flags.Args() {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "File Error: %v\n", err)
+			code = max(code, exitFile)
+			continue
+		}
+
+		program, err := parser.Parse(string(src))
+		if err != nil {
+			for _, e := range err.(lexer.SyntaxErrors) {
+				fmt.Fprintf(os.Stderr, "Syntax Error: %s: %v\n", filename, e)
+			}
+			code = max(code, exitSyntax)
+			continue
+		}
+
+		found := check.Program(program)
+		if *types {
+			found = append(found, check.Types(program)...)
+			sort.SliceStable(found, func(a, b int) bool {
+				pa, pb := found[a].Pos, found[b].Pos
+				return pa.Line < pb.Line || (pa.Line == pb.Line && pa.Column < pb.Column)
+			})
+		}
+		for _, problem := range found {
+			problems = append(problems, checkProblem{
+				File:    filename,
+				Line:    problem.Pos.Line,
+				Column:  problem.Pos.Column,
+				Rule:    problem.Rule,
+				Message: problem.Message,
+			})
+		}
+	}
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(problems, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		for _, problem := range problems {
+			fmt.Printf("%s:%d:%d: %s (%s)\n", problem.File, problem.Line, problem.Column, problem.Message, problem.Rule)
+		}
+	}
+
+	if len(problems) > 0 {
+		code = max(code, exitRuntime)
+	}
+	os.Exit(code)
+}
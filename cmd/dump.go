@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"io"
+	"os"
+)
+
+// dumpToken is how a token is written by hlang tokens. Raw is the string
+// as written, quotes and escapes included, and only set for strings.
+type dumpToken struct {
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Raw    string `json:"raw,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// tokensCommand prints the tokens of a file as a JSON array, also when the
+// lexer reports errors so they can be looked into
+func tokensCommand(args []string) {
+	filename, src := readDumpSource("tokens", args)
+
+	l := lexer.New(src)
+	tokens := []dumpToken{}
+	for _, tok := range l.Tokenize() {
+		tokens = append(tokens, dumpToken{
+			Type:   tok.Type,
+			Value:  tok.Value,
+			Raw:    tok.Raw,
+			Line:   tok.Pos.Line,
+			Column: tok.Pos.Column,
+		})
+	}
+	printJSON(tokens)
+
+	if errs := l.Errors(); len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Fprintf(os.Stderr, "Syntax Error: %s: %v\n", filename, e)
+		}
+		os.Exit(exitSyntax)
+	}
+}
+
+// astCommand prints the program in a file as JSON, see ast.UnmarshalNode
+// for the shape of the nodes
+func astCommand(args []string) {
+	filename, src := readDumpSource("ast", args)
+
+	program, err := parser.Parse(src)
+	if err != nil {
+		for _, e := range err.(lexer.SyntaxErrors) {
+			fmt.Fprintf(os.Stderr, "Syntax Error: %s: %v\n", filename, e)
+		}
+		os.Exit(exitSyntax)
+	}
+	printJSON(program)
+}
+
+// readDumpSource reads the one file tokens and ast take, - is stdin
+func readDumpSource(command string, args []string) (string, string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage Error: Please provide one .hlang file, or - to read it from stdin\n")
+		fmt.Fprintf(os.Stderr, "Usage: ./hlang.exe %s <filename.hlang>\n", command)
+		os.Exit(exitUsage)
+	}
+
+	if args[0] == "-" {
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "File Error: cannot read stdin: %v\n", err)
+			os.Exit(exitFile)
+		}
+		return "<stdin>", string(src)
+	}
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "File Error: %v\n", err)
+		os.Exit(exitFile)
+	}
+	return args[0], string(src)
+}
+
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintf(os.Stderr, "Runtime Error: %v\n", err)
+		os.Exit(exitRuntime)
+	}
+}
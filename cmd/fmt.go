@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/format"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"io"
+	"os"
+)
+
+// fmtCommand formats the given files, printing the result unless -w or
+// --check is given. Without files it formats stdin to stdout.
+func fmtCommand(args []string) {
+	const usage = "./hlang.exe fmt [-w] [--check] [files...]"
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := flags.Bool("w", false, "write the result back to the files instead of printing it")
+	check := flags.Bool("check", false, "only list the files that are not formatted, exiting with 1 if there are any")
+	parseFlags(flags, usage, args)
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "Usage Error: -w needs files to write to")
+			fmt.Fprintln(os.Stderr, "Usage: "+usage)
+			os.Exit(exitUsage)
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "File Error: cannot read stdin: %v\n", err)
+			os.Exit(exitFile)
+		}
+		os.Exit(formatFile("<stdin>", src, false, *check))
+	}
+
+	code := 0
+	for _, filename := range flags.Args() {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "File Error: %v\n", err)
+			code = max(code, exitFile)
+			continue
+		}
+		code = max(code, formatFile(filename, src, *write, *check))
+	}
+	os.Exit(code)
+}
+
+// formatFile formats one file and returns the exit code it calls for
+func formatFile(filename string, src []byte, write, check bool) int {
+	formatted, err := format.Source(string(src))
+	if err != nil {
+		for _, e := range err.(lexer.SyntaxErrors) {
+			fmt.Fprintf(os.Stderr, "Syntax Error: %s: %v\n", filename, e)
+		}
+		return exitSyntax
+	}
+
+	changed := !bytes.Equal(src, []byte(formatted))
+	switch {
+	case check:
+		if changed {
+			fmt.Println(filename)
+			return exitRuntime
+		}
+	case write:
+		if changed {
+			if err := os.WriteFile(filename, []byte(formatted), 0o644); err != nil {
+				fmt.Fprintf(os.Stderr, "File Error: %v\n", err)
+				return exitFile
+			}
+		}
+	default:
+		fmt.Print(formatted)
+	}
+	return 0
+}
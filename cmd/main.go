@@ -1,60 +1,207 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"github.com/suraj-9849/hindiLang.git/config/functions"
+	"github.com/suraj-9849/hindiLang.git/interp"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Exit codes, so scripts and CI can tell what kind of error stopped a
+// program. A program calling bahar(n) exits with n instead.
+const (
+	exitRuntime = 1 // the program failed while running
+	exitUsage   = 2 // the command line was wrong
+	exitFile    = 3 // the program file could not be read
+	exitSyntax  = 4 // the program does not parse, nothing was run
+	exitLimit   = 5 // the program ran into --timeout, --max-steps or --max-memory
 )
 
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 
 	command := os.Args[1]
 
 	switch command {
 	case "run":
-		if len(os.Args) < 3 {
-			fmt.Println("Error: Please provide a .hlang file to run")
-			fmt.Println("Usage: ./hlang.exe run <filename.hlang>")
-			os.Exit(1)
-		}
-		runFile(os.Args[2])
+		runCommand(os.Args[2:])
+	case "eval":
+		evalCommand(os.Args[2:])
+	case "repl":
+		os.Exit(runRepl())
+	case "fmt":
+		fmtCommand(os.Args[2:])
+	case "check":
+		checkCommand(os.Args[2:])
+	case "tokens":
+		tokensCommand(os.Args[2:])
+	case "ast":
+		astCommand(os.Args[2:])
 	case "version", "-v", "--version":
 		fmt.Println("HindiScript v1.0.0")
 		fmt.Println("A programming language in Hindi")
 	case "help", "-h", "--help":
 		printUsage()
 	default:
-		fmt.Printf("Unknown command: %s\n", command)
+		// "#!/usr/bin/env hlang" runs a script as "hlang <file>"
+		if info, err := os.Stat(command); err == nil && !info.IsDir() {
+			runFile(command, interp.WithArgs(os.Args[2:]))
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Usage Error: unknown command %s\n", command)
 		printUsage()
-		os.Exit(1)
+		os.Exit(exitUsage)
 	}
 }
 
-func runFile(filename string) {
-	if _, err := os.Stat(filename); os.IsNotExist(err) {
-		fmt.Printf("Error: File '%s' not found\n", filename)
-		os.Exit(1)
+// limitFlags are the flags shared by run and eval that limit a program
+type limitFlags struct {
+	timeout   *time.Duration
+	maxSteps  *int
+	maxMemory *int
+}
+
+func addLimitFlags(flags *flag.FlagSet) *limitFlags {
+	return &limitFlags{
+		timeout:   flags.Duration("timeout", 0, "stop the program after this long, e.g. 5s"),
+		maxSteps:  flags.Int("max-steps", 0, "stop the program after this many loop iterations and calls"),
+		maxMemory: flags.Int("max-memory", 0, "stop the program once its values take more than this many bytes"),
 	}
+}
 
-	ext := filepath.Ext(filename)
-	if ext != ".hlang" {
-		fmt.Printf("Warning: File '%s' does not have .hlang extension\n", filename)
+func (l *limitFlags) options() ([]interp.Option, context.CancelFunc) {
+	opts := []interp.Option{
+		interp.WithStepLimit(*l.maxSteps),
+		interp.WithMemoryLimit(*l.maxMemory),
+	}
+	if *l.timeout <= 0 {
+		return opts, func() {}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *l.timeout)
+	return append(opts, interp.WithContext(ctx)), cancel
+}
+
+// parseFlags parses the flags of a command. A flag it doesn't know or a
+// bad flag value is a Usage Error like any other mistake on the command
+// line, -h prints the usage and the flags.
+func parseFlags(flags *flag.FlagSet, usage string, args []string) {
+	flags.SetOutput(io.Discard)
+	err := flags.Parse(args)
+	if err == nil {
+		return
+	}
+
+	out, code := io.Writer(os.Stderr), exitUsage
+	if errors.Is(err, flag.ErrHelp) {
+		out, code = os.Stdout, 0
+	} else {
+		fmt.Fprintf(out, "Usage Error: %v\n", err)
+	}
+	fmt.Fprintf(out, "Usage: %s\n", usage)
+	flags.SetOutput(out)
+	flags.PrintDefaults()
+	os.Exit(code)
+}
+
+func runCommand(args []string) {
+	const usage = "./hlang.exe run [--timeout 5s] [--max-steps N] [--max-memory BYTES] <filename.hlang>"
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	limits := addLimitFlags(flags)
+	parseFlags(flags, usage, args)
+
+	if flags.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage Error: Please provide a .hlang file to run, or - to read it from stdin")
+		fmt.Fprintln(os.Stderr, "Usage: "+usage)
+		os.Exit(exitUsage)
+	}
+
+	opts, cancel := limits.options()
+	defer cancel()
+	// everything after the file name belongs to the script
+	opts = append(opts, interp.WithArgs(flags.Args()[1:]))
+
+	if flags.Arg(0) == "-" {
+		code, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "File Error: cannot read stdin: %v\n", err)
+			os.Exit(exitFile)
+		}
+		runCode(string(code), opts...)
+		return
+	}
+	runFile(flags.Arg(0), opts...)
+}
+
+func evalCommand(args []string) {
+	const usage = "./hlang.exe eval [--timeout 5s] [--max-steps N] [--max-memory BYTES] -e 'bol(1 + 2)'"
+	flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+	code := flags.String("e", "", "the code to run")
+	limits := addLimitFlags(flags)
+	parseFlags(flags, usage, args)
+
+	if *code == "" {
+		fmt.Fprintln(os.Stderr, "Usage Error: Please provide code to run")
+		fmt.Fprintln(os.Stderr, "Usage: "+usage)
+		os.Exit(exitUsage)
+	}
+
+	opts, cancel := limits.options()
+	defer cancel()
+	opts = append(opts, interp.WithArgs(flags.Args()))
+	runCode(*code, opts...)
+}
+
+func runFile(filename string, opts ...interp.Option) {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "File Error: File '%s' not found\n", filename)
+		os.Exit(exitFile)
 	}
 
 	code, err := os.ReadFile(filename)
 	if err != nil {
-		fmt.Printf("Error reading file: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "File Error: %v\n", err)
+		os.Exit(exitFile)
 	}
 
-	err = functions.Run(string(code))
-	if err != nil {
-		os.Exit(1)
+	// executable scripts with a #! line usually have no extension
+	ext := filepath.Ext(filename)
+	if ext != ".hlang" && !strings.HasPrefix(string(code), "#!") {
+		fmt.Fprintf(os.Stderr, "Warning: File '%s' does not have .hlang extension\n", filename)
+	}
+
+	runCode(string(code), opts...)
+}
+
+// runCode runs a program, Run has already printed its errors so only the
+// exit code is left to pick
+func runCode(code string, opts ...interp.Option) {
+	if err := interp.Run(code, opts...); err != nil {
+		os.Exit(exitCode(err))
+	}
+}
+
+func exitCode(err error) int {
+	var exit *interp.ExitError
+	var syntax lexer.SyntaxErrors
+	switch {
+	case errors.As(err, &exit):
+		return exit.Code
+	case errors.As(err, &syntax):
+		return exitSyntax
+	case interp.IsLimitError(err):
+		return exitLimit
+	default:
+		return exitRuntime
 	}
 }
 
@@ -62,8 +209,29 @@ func printUsage() {
 	fmt.Println("HindiScript - A programming language in Hindi")
 	fmt.Println()
 	fmt.Println("Usage:")
-	fmt.Println("./hlang.exe run <filename.hlang>    Run a .hlang file")
+	fmt.Println("./hlang.exe run <filename.hlang> [args...]")
+	fmt.Println("                                    Run a .hlang file, args are in the list tarks")
+	fmt.Println("    --timeout 5s                    Stop the program after a time limit")
+	fmt.Println("    --max-steps N                   Stop after N loop iterations and calls")
+	fmt.Println("    --max-memory BYTES              Stop once values take more than BYTES")
+	fmt.Println("./hlang.exe run -                   Run a program read from stdin")
+	fmt.Println("./hlang.exe eval -e '<code>'        Run code given on the command line")
+	fmt.Println("./hlang.exe <filename.hlang>        Run a file, for scripts starting with #!/usr/bin/env hlang")
+	fmt.Println("./hlang.exe repl                    Start an interactive session")
+	fmt.Println("./hlang.exe fmt [-w] [--check] <files...>")
+	fmt.Println("                                    Format files, printing them, writing them back (-w)")
+	fmt.Println("                                    or listing the ones that need formatting (--check)")
+	fmt.Println("./hlang.exe check [--json] [--types] <files...>")
+	fmt.Println("                                    Report likely mistakes without running the files,")
+	fmt.Println("                                    --types also checks the types of values")
+	fmt.Println("./hlang.exe tokens <filename.hlang> Print the tokens of a file as JSON")
+	fmt.Println("./hlang.exe ast <filename.hlang>    Print the syntax tree of a file as JSON")
 	fmt.Println("./hlang.exe version                 Show version information")
 	fmt.Println("./hlang.exe help                    Show this help message")
 	fmt.Println()
-}
\ No newline at end of file
+	fmt.Println("Exit codes:")
+	fmt.Println("    1  runtime error        2  wrong command line    3  file cannot be read")
+	fmt.Println("    4  syntax error         5  time, step or memory limit reached")
+	fmt.Println("    a program calling bahar(n) exits with n")
+	fmt.Println()
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/interp"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// console is where the REPL reads lines and writes output. On a terminal
+// it gives line editing and history, otherwise it reads plain lines.
+type console interface {
+	io.Writer
+	ReadLine(prompt string) (string, error)
+}
+
+// errInterrupted is what ReadLine returns for Ctrl+C, the REPL drops the
+// input typed so far
+var errInterrupted = errors.New("interrupted")
+
+// terminalConsole puts the terminal in raw mode only while it reads a
+// line, so Ctrl+C stops a running program with SIGINT as usual
+type terminalConsole struct {
+	fd    int
+	rw    io.ReadWriter
+	input *interruptReader
+	term  *term.Terminal
+}
+
+func newTerminalConsole(fd int) *terminalConsole {
+	input := &interruptReader{Reader: os.Stdin}
+	rw := struct {
+		io.Reader
+		io.Writer
+	}{input, os.Stdout}
+	return &terminalConsole{fd: fd, rw: rw, input: input, term: term.NewTerminal(rw, "")}
+}
+
+func (c *terminalConsole) Write(p []byte) (int, error) {
+	return c.term.Write(p)
+}
+
+func (c *terminalConsole) ReadLine(prompt string) (string, error) {
+	state, err := term.MakeRaw(c.fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(c.fd, state)
+
+	c.input.interrupted = false
+	c.term.SetPrompt(prompt)
+	line, err := c.term.ReadLine()
+	if err == io.EOF && c.input.interrupted {
+		// x/term gives io.EOF for Ctrl+C too and keeps the half typed
+		// line, so go on with a fresh terminal that has the same history
+		fresh := term.NewTerminal(c.rw, "")
+		fresh.History = c.term.History
+		c.term = fresh
+		fmt.Fprintln(c.term, "^C")
+		return "", errInterrupted
+	}
+	return line, err
+}
+
+// interruptReader notes when a Ctrl+C is read from the terminal
+type interruptReader struct {
+	io.Reader
+	interrupted bool
+}
+
+func (r *interruptReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if bytes.IndexByte(p[:n], 3) >= 0 {
+		r.interrupted = true
+	}
+	return n, err
+}
+
+// plainConsole is used when stdin is a pipe or a file, prompts are left
+// out so the output is only what the program printed
+type plainConsole struct {
+	io.Writer
+	scanner *bufio.Scanner
+}
+
+func (c plainConsole) ReadLine(prompt string) (string, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return c.scanner.Text(), nil
+}
+
+// consoleReader lets pucho read its input through the console, so it gets
+// the same line editing as the REPL itself
+type consoleReader struct {
+	con     console
+	pending []byte
+}
+
+func (r *consoleReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		line, err := r.con.ReadLine("")
+		if err != nil {
+			return 0, err
+		}
+		r.pending = []byte(line + "\n")
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// runRepl returns the exit code asked for with bahar(), or 0
+func runRepl() int {
+	var con console
+	fd := int(os.Stdin.Fd())
+	interactive := term.IsTerminal(fd)
+	if interactive {
+		con = newTerminalConsole(fd)
+		fmt.Fprintln(con, "HindiScript v1.0.0 REPL")
+		fmt.Fprintln(con, "Type .help for help, Ctrl+C to stop a program, Ctrl+D to exit")
+	} else {
+		con = plainConsole{Writer: os.Stdout, scanner: bufio.NewScanner(os.Stdin)}
+	}
+
+	interpreter := newReplInterpreter(con)
+	input := ""
+	for {
+		prompt := ">> "
+		if input != "" {
+			prompt = ".. "
+		}
+		line, err := con.ReadLine(prompt)
+		if errors.Is(err, errInterrupted) {
+			input = ""
+			continue
+		}
+		if err != nil {
+			return 0
+		}
+
+		if command := strings.TrimSpace(line); strings.HasPrefix(command, ".") {
+			switch command {
+			case ".help":
+				printReplHelp(con)
+			case ".env":
+				printReplEnv(con, interpreter)
+			case ".clear":
+				interpreter = newReplInterpreter(con)
+				input = ""
+				fmt.Fprintln(con, "Environment cleared")
+			case ".exit":
+				return 0
+			default:
+				fmt.Fprintf(con, "Unknown command: %s, type .help for help\n", command)
+			}
+			continue
+		}
+
+		input += line + "\n"
+		if openBrackets(input) > 0 {
+			continue
+		}
+		if exit := evalReplInput(con, interpreter, input, interactive); exit != nil {
+			return exit.Code
+		}
+		input = ""
+	}
+}
+
+func newReplInterpreter(con console) *interp.Interpreter {
+	return interp.NewInterpreter(
+		interp.WithStdout(con),
+		interp.WithStderr(con),
+		interp.WithStdin(&consoleReader{con: con}),
+	)
+}
+
+// openBrackets counts the { and ( still waiting to be closed, the REPL
+// keeps reading lines until there are none
+func openBrackets(input string) int {
+	depth := 0
+	for _, tok := range lexer.Tokenize(input) {
+		switch tok.Value {
+		case "{", "(":
+			depth++
+		case "}", ")":
+			depth--
+		}
+	}
+	return depth
+}
+
+// evalReplInput runs one input. On a terminal Ctrl+C stops it and the
+// REPL goes on.
+func evalReplInput(con console, interpreter *interp.Interpreter, input string, interactive bool) *interp.ExitError {
+	program, err := parser.Parse(input)
+	if err != nil {
+		for _, msg := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(con, "Syntax Error: %s\n", msg)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	if interactive {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+	}
+	val, err := interpreter.RunContext(ctx, program)
+	if err != nil {
+		var exit *interp.ExitError
+		if errors.As(err, &exit) {
+			return exit
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, errInterrupted) {
+			if ctx.Err() != nil {
+				// the terminal echoed ^C without a line end
+				fmt.Fprintln(con)
+			}
+			fmt.Fprintln(con, "Interrupted")
+			return nil
+		}
+		var rtErr *interp.RuntimeError
+		if errors.As(err, &rtErr) {
+			fmt.Fprint(con, rtErr.Traceback(input))
+			fmt.Fprintf(con, "Runtime Error: %s\n", rtErr.Message)
+		} else {
+			fmt.Fprintf(con, "Runtime Error: %v\n", err)
+		}
+		return nil
+	}
+
+	if len(program.Body) == 0 || !isExpression(program.Body[len(program.Body)-1]) {
+		return nil
+	}
+	if _, ok := val.(*interp.NullValue); !ok {
+		fmt.Fprintln(con, interpreter.Format(val))
+	}
+	return nil
+}
+
+// isExpression reports whether a statement's value is worth echoing, which
+// is not the case for declarations and control flow
+func isExpression(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.Identifier, *ast.Literal, *ast.TemplateLiteral,
+		*ast.BinaryExpression, *ast.FunctionCall:
+		return true
+	default:
+		return false
+	}
+}
+
+func printReplEnv(con console, interpreter *interp.Interpreter) {
+	globals := interpreter.Globals()
+	for _, name := range globals.Names() {
+		val, _ := globals.Get(name)
+		if _, ok := val.(*interp.NativeFunction); ok {
+			continue
+		}
+		fmt.Fprintf(con, "%s = %s\n", name, interpreter.Format(val))
+	}
+}
+
+func printReplHelp(con console) {
+	fmt.Fprintln(con, "Type HindiScript code, expression values are printed.")
+	fmt.Fprintln(con, "Input with unclosed { or ( continues on the next line.")
+	fmt.Fprintln(con)
+	fmt.Fprintln(con, ".help     Show this help")
+	fmt.Fprintln(con, ".env      Show the variables and functions defined so far")
+	fmt.Fprintln(con, ".clear    Forget all variables and functions")
+	fmt.Fprintln(con, ".exit     Leave the REPL")
+}
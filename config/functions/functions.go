@@ -0,0 +1,37 @@
+// Package functions is where HindiScript used to live before it was split
+// into the token, lexer, ast, parser and interp packages. It keeps Run and
+// the types its callers need working.
+//
+// Deprecated: use the interp package, and the others for the parts of
+// the language they implement.
+package functions
+
+import (
+	"github.com/suraj-9849/hindiLang.git/interp"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+)
+
+// Option configures the interpreter a program is run with, see the With
+// functions of the interp package
+type Option = interp.Option
+
+// SyntaxErrors is returned by Run for code that does not parse
+type SyntaxErrors = lexer.SyntaxErrors
+
+// RuntimeError is returned by Run for a program that failed while running
+type RuntimeError = interp.RuntimeError
+
+// ExitError is returned by Run when a program calls bahar()
+type ExitError = interp.ExitError
+
+// Run lexes, parses and runs a program, printing any errors to stderr,
+// see interp.Run
+func Run(code string, opts ...Option) error {
+	return interp.Run(code, opts...)
+}
+
+// IsLimitError reports whether err stopped a program because it ran out
+// of one of its limits, see interp.IsLimitError
+func IsLimitError(err error) bool {
+	return interp.IsLimitError(err)
+}
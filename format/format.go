@@ -0,0 +1,407 @@
+// Package format prints HindiScript source in its canonical style, the
+// way hlang fmt does.
+package format
+
+import (
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"github.com/suraj-9849/hindiLang.git/token"
+	"strconv"
+	"strings"
+)
+
+// Source parses src and prints it back in the canonical style: one
+// statement per line, tabs for indentation, single spaces around binary
+// operators, conditions without parentheses and "ya"/"ya fir" on the line
+// of the "}" before them. Comments are kept, and so is a single blank
+// line wherever the source had one or more. Comments inside a statement,
+// which is printed on one line, go after its code on that line.
+// Formatting formatted source changes nothing.
+//
+// Source that does not parse is not formatted, the error is
+// lexer.SyntaxErrors.
+func Source(src string) (string, error) {
+	l := lexer.New(src)
+	tokens := l.Tokenize()
+	p := parser.New(tokens)
+	program := p.ParseProgram()
+	if errs := append(l.Errors(), p.Errors()...); len(errs) > 0 {
+		return "", lexer.SyntaxErrors(errs)
+	}
+
+	pr := &printer{
+		tokens:   tokens,
+		index:    make(map[token.Position]int, len(tokens)),
+		comments: l.Comments(),
+	}
+	for idx, tok := range tokens {
+		pr.index[tok.Pos] = idx
+	}
+
+	// the lexer skips a #! line, it is copied as it is
+	if strings.HasPrefix(src, "#!") {
+		shebang, _, _ := strings.Cut(src, "\n")
+		pr.write(strings.TrimRight(shebang, " \t\r"))
+		pr.newline()
+		pr.lastLine = 1
+	}
+
+	for _, stmt := range program.Body {
+		pr.statement(stmt)
+	}
+	pr.commentsBefore(token.Position{Line: int(^uint(0) >> 1)})
+	return pr.out.String(), nil
+}
+
+// printer writes the formatted program line by line. Comments are not in
+// the AST, they are printed when the statement after them is, using the
+// positions the lexer recorded for both.
+type printer struct {
+	out    strings.Builder
+	line   strings.Builder
+	indent int
+
+	tokens   []token.Token
+	index    map[token.Position]int
+	comments []token.Comment
+	next     int
+
+	// lastLine is the source line printed last, a statement starting more
+	// than one line after it had a blank line before it
+	lastLine   int
+	blockStart bool
+}
+
+func (p *printer) write(text string) {
+	p.line.WriteString(text)
+}
+
+func (p *printer) newline() {
+	if p.line.Len() > 0 {
+		p.out.WriteString(strings.Repeat("\t", p.indent))
+		p.out.WriteString(p.line.String())
+	}
+	p.out.WriteString("\n")
+	p.line.Reset()
+}
+
+// blankLine keeps one blank line before something starting at line when
+// the source had at least one, except right after a "{"
+func (p *printer) blankLine(line int) {
+	if !p.blockStart && p.lastLine > 0 && line > p.lastLine+1 {
+		p.out.WriteString("\n")
+	}
+	p.blockStart = false
+}
+
+// annotation prints the ": type" after a name, if it has one
+func annotation(typ string) string {
+	if typ == "" {
+		return ""
+	}
+	return ": " + typ
+}
+
+// commentsBefore prints the comments that come before pos, each on a line
+// of its own
+func (p *printer) commentsBefore(pos token.Position) {
+	for p.next < len(p.comments) && p.comments[p.next].Pos.Before(pos) {
+		comment := p.comments[p.next]
+		p.next++
+		p.blankLine(comment.Pos.Line)
+		p.write(comment.Text)
+		p.newline()
+		p.lastLine = max(p.lastLine, comment.Pos.Line+strings.Count(comment.Text, "\n"))
+	}
+}
+
+// trailingComments appends the comments on the given source line to the
+// output line, stopping at limit
+func (p *printer) trailingComments(line int, limit token.Position) {
+	for p.next < len(p.comments) && p.comments[p.next].Pos.Line == line && p.comments[p.next].Pos.Before(limit) {
+		comment := p.comments[p.next]
+		p.next++
+		p.inlineComment(comment)
+	}
+}
+
+// commentsWithin takes the comments before limit that are inside the
+// statement being printed. Its code is printed on one line, so they can't
+// stay where they were and go after the code instead of before the next
+// statement.
+func (p *printer) commentsWithin(limit token.Position) []token.Comment {
+	start := p.next
+	for p.next < len(p.comments) && p.comments[p.next].Pos.Before(limit) {
+		p.next++
+	}
+	return p.comments[start:p.next]
+}
+
+// inlineComment appends a comment to the output line
+func (p *printer) inlineComment(comment token.Comment) {
+	p.write(" " + comment.Text)
+	p.lastLine = max(p.lastLine, comment.Pos.Line+strings.Count(comment.Text, "\n"))
+}
+
+func (p *printer) statement(node ast.Node) {
+	start := ast.Start(node)
+	p.commentsBefore(start)
+	p.blankLine(start.Line)
+
+	switch n := node.(type) {
+	case *ast.Declaration:
+		p.write("ye " + n.Name + annotation(n.Type) + " = " + p.expr(n.Value, 0))
+	case *ast.Assignment:
+		p.write(n.Name + " = " + p.expr(n.Value, 0))
+	case *ast.FunctionDeclaration:
+		params := make([]string, len(n.Parameters))
+		for idx, param := range n.Parameters {
+			params[idx] = param.Name + annotation(param.Type)
+			if param.Rest {
+				params[idx] = "..." + params[idx]
+			}
+			if param.Default != nil {
+				params[idx] += " = " + p.expr(param.Default, 0)
+			}
+		}
+		p.write("firseKaro " + n.Name + "(" + strings.Join(params, ", ") + ")")
+		p.write(annotation(n.ReturnType) + " ")
+		p.block(n.Body, n.Pos)
+	case *ast.IfStatement:
+		p.ifStatement(n)
+	case *ast.WhileLoop:
+		p.write("jabtak " + p.expr(n.Condition, 0) + " ")
+		p.block(n.Body, n.Pos)
+	case *ast.RepeatLoop:
+		p.write("dohraye ")
+		p.block(n.Body, n.Pos)
+	case *ast.BreakStatement:
+		p.write("roko")
+	case *ast.ContinueStatement:
+		p.write("aage badho")
+	case *ast.ReturnStatement:
+		p.write("wapas bhejo")
+		if n.Value != nil {
+			p.write(" " + p.expr(n.Value, 0))
+		}
+	default:
+		p.write(p.expr(node, 0))
+	}
+
+	last := p.endToken(node)
+	end := tokenEndLine(p.tokens[last])
+	for _, comment := range p.commentsWithin(p.tokens[last].Pos) {
+		p.inlineComment(comment)
+	}
+	p.lastLine = max(p.lastLine, end)
+	p.trailingComments(end, p.nextOnLine(last))
+	p.newline()
+}
+
+func (p *printer) ifStatement(n *ast.IfStatement) {
+	p.write("agar " + p.expr(n.Condition, 0) + " ")
+	closing := p.block(n.Consequent, n.Pos)
+
+	for _, elseIf := range n.ElseIfs {
+		p.elseKeyword(elseIf.Pos)
+		p.write("ya fir " + p.expr(elseIf.Condition, 0) + " ")
+		closing = p.block(elseIf.Consequent, elseIf.Pos)
+	}
+
+	// "ya" has no position of its own in the AST, it is the token after
+	// the last "}"
+	idx := closing + 1
+	if idx < len(p.tokens) && p.tokens[idx].Value == "\n" {
+		idx++
+	}
+	if idx < len(p.tokens) && p.tokens[idx].Type == token.Keyword && p.tokens[idx].Value == "ya" {
+		pos := p.tokens[idx].Pos
+		p.elseKeyword(pos)
+		p.write("ya ")
+		p.block(n.Alternate, pos)
+	}
+}
+
+// elseKeyword puts "ya" or "ya fir" after the "}" on the same line, unless
+// comments come in between
+func (p *printer) elseKeyword(pos token.Position) {
+	if p.next < len(p.comments) && p.comments[p.next].Pos.Before(pos) {
+		p.newline()
+		p.blockStart = true
+		p.commentsBefore(pos)
+		return
+	}
+	p.write(" ")
+}
+
+// block prints the "{ ... }" of the statement at header and returns the
+// index of its closing brace token
+func (p *printer) block(body []ast.Node, header token.Position) int {
+	open, closing := p.braces(header)
+	openPos, closePos := p.tokens[open].Pos, p.tokens[closing].Pos
+
+	// comments in the header stay on its line, a // comment can only
+	// come after the "{"
+	var lineComments []token.Comment
+	for _, comment := range p.commentsWithin(openPos) {
+		if strings.HasPrefix(comment.Text, "//") {
+			lineComments = append(lineComments, comment)
+			continue
+		}
+		p.write(comment.Text + " ")
+		p.lastLine = max(p.lastLine, comment.Pos.Line+strings.Count(comment.Text, "\n"))
+	}
+
+	limit := closePos
+	if len(body) > 0 {
+		limit = ast.Start(body[0])
+	}
+	if len(body) == 0 && len(lineComments) == 0 && (p.next >= len(p.comments) || !p.comments[p.next].Pos.Before(closePos)) {
+		p.write("{}")
+		p.lastLine = closePos.Line
+		return closing
+	}
+
+	p.write("{")
+	for _, comment := range lineComments {
+		p.inlineComment(comment)
+	}
+	p.trailingComments(openPos.Line, limit)
+	p.newline()
+	p.lastLine = openPos.Line
+	p.blockStart = true
+	p.indent++
+	for _, stmt := range body {
+		p.statement(stmt)
+	}
+	p.commentsBefore(closePos)
+	p.indent--
+	p.blockStart = false
+
+	p.write("}")
+	p.lastLine = closePos.Line
+	return closing
+}
+
+// braces finds the "{" of the block belonging to the statement at header
+// and its matching "}". Conditions cannot contain braces, so it is the
+// first one after the header.
+func (p *printer) braces(header token.Position) (int, int) {
+	open := p.index[header]
+	for p.tokens[open].Value != "{" || p.tokens[open].Type != token.Brace {
+		open++
+	}
+	depth := 0
+	for idx := open; idx < len(p.tokens); idx++ {
+		if p.tokens[idx].Type != token.Brace {
+			continue
+		}
+		if p.tokens[idx].Value == "{" {
+			depth++
+		} else if depth--; depth == 0 {
+			return open, idx
+		}
+	}
+	return open, len(p.tokens) - 1
+}
+
+// endToken is the index of the last token of a statement, found by
+// scanning its tokens up to the separator after it
+func (p *printer) endToken(node ast.Node) int {
+	idx := p.index[ast.Start(node)]
+	last := idx
+	depth := 0
+	for ; idx < len(p.tokens); idx++ {
+		tok := p.tokens[idx]
+		switch tok.Type {
+		case token.Semicolon:
+			if depth > 0 {
+				continue
+			}
+			// "}" and "ya" may be on different lines
+			if next := idx + 1; tok.Value == "\n" && next < len(p.tokens) &&
+				p.tokens[next].Type == token.Keyword && (p.tokens[next].Value == "ya" || p.tokens[next].Value == "ya fir") {
+				continue
+			}
+			return last
+		case token.DocComment:
+			continue
+		case token.Brace:
+			if tok.Value == "{" {
+				depth++
+			} else if depth == 0 {
+				return last
+			} else {
+				depth--
+			}
+		}
+		last = idx
+	}
+	return last
+}
+
+// nextOnLine is where the statement after the token at last starts when
+// it is on the same line, the comments of that line up to it belong to
+// the statement ending at last
+func (p *printer) nextOnLine(last int) token.Position {
+	end := tokenEndLine(p.tokens[last])
+	idx := last + 1
+	for idx < len(p.tokens) && p.tokens[idx].Type == token.Semicolon {
+		idx++
+	}
+	if idx < len(p.tokens) && p.tokens[idx].Pos.Line == end {
+		return p.tokens[idx].Pos
+	}
+	return token.Position{Line: end + 1}
+}
+
+// tokenEndLine is the line a token ends on, strings may span lines
+func tokenEndLine(tok token.Token) int {
+	return tok.Pos.Line + strings.Count(tok.Raw, "\n")
+}
+
+// expr prints an expression, with parentheses only where the precedence
+// of the operators needs them
+func (p *printer) expr(node ast.Node, prec int) string {
+	switch n := node.(type) {
+	case *ast.Identifier:
+		return n.Name
+	case *ast.Literal:
+		if idx, ok := p.index[n.Pos]; ok && p.tokens[idx].Raw != "" {
+			return p.tokens[idx].Raw
+		}
+		if n.IsNumber() {
+			return n.Value
+		}
+		return strconv.Quote(n.Value)
+	case *ast.TemplateLiteral:
+		if idx, ok := p.index[n.Pos]; ok && p.tokens[idx].Raw != "" {
+			return p.tokens[idx].Raw
+		}
+		var sb strings.Builder
+		sb.WriteString(`"`)
+		for idx, expr := range n.Expressions {
+			sb.WriteString(n.Quasis[idx] + "{" + p.expr(expr, 0) + "}")
+		}
+		sb.WriteString(n.Quasis[len(n.Quasis)-1] + `"`)
+		return sb.String()
+	case *ast.FunctionCall:
+		args := make([]string, len(n.Arguments))
+		for idx, arg := range n.Arguments {
+			args[idx] = p.expr(arg, 0)
+		}
+		return n.Name + "(" + strings.Join(args, ", ") + ")"
+	case *ast.BinaryExpression:
+		opPrec := token.Precedence(n.Operator)
+		// operators group to the left, so an equal one on the right keeps
+		// its parentheses
+		text := p.expr(n.Left, opPrec) + " " + n.Operator + " " + p.expr(n.Right, opPrec+1)
+		if opPrec < prec {
+			return "(" + text + ")"
+		}
+		return text
+	default:
+		return ""
+	}
+}
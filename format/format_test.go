@@ -0,0 +1,89 @@
+package format
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the formatted testdata/*.input files to their .golden files")
+
+// TestGolden formats each testdata/*.input file and compares the result
+// to the .golden file next to it, then checks that formatting the result
+// again changes nothing
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.input"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range inputs {
+		golden := strings.TrimSuffix(input, ".input") + ".golden"
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			src, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Source(string(src))
+			if err != nil {
+				t.Fatalf("formatting: %v", err)
+			}
+
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("formatted:\n%s\nwant:\n%s", got, want)
+			}
+
+			again, err := Source(got)
+			if err != nil {
+				t.Fatalf("formatting the result: %v", err)
+			}
+			if again != got {
+				t.Errorf("formatting twice changed the result:\n%s\nthe first time:\n%s", again, got)
+			}
+		})
+	}
+}
+
+// TestExamples checks that formatting the example programs twice gives
+// the same result
+func TestExamples(t *testing.T) {
+	examples, err := filepath.Glob(filepath.Join("..", "examples", "*.hlang"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, example := range examples {
+		t.Run(filepath.Base(example), func(t *testing.T) {
+			src, err := os.ReadFile(example)
+			if err != nil {
+				t.Fatal(err)
+			}
+			once, err := Source(string(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			twice, err := Source(once)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if once != twice {
+				t.Errorf("formatting twice changed the result:\n%s\nthe first time:\n%s", twice, once)
+			}
+		})
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	if _, err := Source("ye = 1"); err == nil || !strings.Contains(err.Error(), "expected a name after ye") {
+		t.Errorf("error = %v, want the syntax error", err)
+	}
+}
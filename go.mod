@@ -1,3 +1,7 @@
 module github.com/suraj-9849/hindiLang.git
 
 go 1.25.1
+
+require golang.org/x/term v0.45.0
+
+require golang.org/x/sys v0.47.0 // indirect
@@ -0,0 +1,176 @@
+package interp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func (i *Interpreter) registerBuiltins() {
+	i.RegisterFunc("bol", -1, i.builtinBol)
+	i.RegisterFunc("pucho", -1, i.builtinPucho)
+	i.RegisterFunc("sankhya", 1, i.builtinSankhya)
+	i.RegisterFunc("lambai", 1, i.builtinLambai)
+	i.RegisterFunc("nikalo", 2, i.builtinNikalo)
+	i.RegisterFunc("mahaul", 1, i.builtinMahaul)
+	i.RegisterFunc("bahar", -1, i.builtinBahar)
+	i.globals().Define("tarks", &ListValue{Elements: []RuntimeValue{}})
+}
+
+// ExitError is returned when a program calls bahar(code), Code is the
+// exit code it asked for
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// WithArgs makes args available to the program as the list tarks
+func WithArgs(args []string) Option {
+	return func(i *Interpreter) {
+		list := &ListValue{Elements: make([]RuntimeValue, len(args))}
+		for idx, arg := range args {
+			list.Elements[idx] = &StringValue{Value: arg}
+		}
+		i.globals().Define("tarks", list)
+	}
+}
+
+// WithEnv replaces os.LookupEnv as the source of mahaul(), e.g. to hide
+// the host's environment from untrusted scripts
+func WithEnv(lookup func(name string) (string, bool)) Option {
+	return func(i *Interpreter) {
+		i.lookupEnv = lookup
+	}
+}
+
+// bol(values...) prints each value on its own line
+func (i *Interpreter) builtinBol(args []RuntimeValue) (RuntimeValue, error) {
+	for _, val := range args {
+		fmt.Fprintln(i.stdout, i.toString(val))
+	}
+	return &NullValue{}, nil
+}
+
+// pucho(prompt) prints the prompt and reads one line of input. It gives
+// null once the input has ended.
+func (i *Interpreter) builtinPucho(args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("expects at most 1 argument, got %d", len(args))
+	}
+	if len(args) == 1 {
+		fmt.Fprint(i.stdout, i.toString(args[0]))
+	}
+
+	if i.input == nil {
+		i.input = bufio.NewReader(i.stdin)
+	}
+	line, err := i.input.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err == io.EOF && line == "" {
+		return &NullValue{}, nil
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+	if err := i.allocate(len(line)); err != nil {
+		return nil, err
+	}
+	return &StringValue{Value: line}, nil
+}
+
+// sankhya(value) turns a string like " 42 " into the number 42
+func (i *Interpreter) builtinSankhya(args []RuntimeValue) (RuntimeValue, error) {
+	switch v := args[0].(type) {
+	case *NumberValue:
+		return v, nil
+	case *StringValue:
+		num, err := strconv.ParseFloat(strings.TrimSpace(v.Value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to a number", v.Value)
+		}
+		return &NumberValue{Value: num}, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %s to a number", args[0].Type())
+	}
+}
+
+// lambai(value) is the number of elements of a list, fields of a record
+// or characters of a string
+func (i *Interpreter) builtinLambai(args []RuntimeValue) (RuntimeValue, error) {
+	switch v := args[0].(type) {
+	case *ListValue:
+		return &NumberValue{Value: float64(len(v.Elements))}, nil
+	case *RecordValue:
+		return &NumberValue{Value: float64(len(v.Keys))}, nil
+	case *StringValue:
+		return &NumberValue{Value: float64(len([]rune(v.Value)))}, nil
+	default:
+		return nil, fmt.Errorf("cannot take the length of %s", args[0].Type())
+	}
+}
+
+// nikalo(collection, key) gets a list element by its 0-based index or a
+// record field by its name
+func (i *Interpreter) builtinNikalo(args []RuntimeValue) (RuntimeValue, error) {
+	switch v := args[0].(type) {
+	case *ListValue:
+		idx, err := NumberArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		if idx != float64(int(idx)) || idx < 0 || int(idx) >= len(v.Elements) {
+			return nil, fmt.Errorf("index %v out of range for list of length %d", idx, len(v.Elements))
+		}
+		return v.Elements[int(idx)], nil
+	case *RecordValue:
+		key, err := StringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		val, ok := v.Fields[key]
+		if !ok {
+			return nil, fmt.Errorf("record has no field %q", key)
+		}
+		return val, nil
+	default:
+		return nil, fmt.Errorf("cannot take an element of %s", args[0].Type())
+	}
+}
+
+// mahaul(name) reads an environment variable, null when it isn't set
+func (i *Interpreter) builtinMahaul(args []RuntimeValue) (RuntimeValue, error) {
+	name, err := StringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := i.lookupEnv(name)
+	if !ok {
+		return &NullValue{}, nil
+	}
+	return &StringValue{Value: val}, nil
+}
+
+// bahar(code) ends the program, the CLI exits with code (0 if not given)
+func (i *Interpreter) builtinBahar(args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("expects at most 1 argument, got %d", len(args))
+	}
+	code := 0
+	if len(args) == 1 {
+		num, err := NumberArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		if num != float64(int(num)) || num < 0 || num > 255 {
+			return nil, fmt.Errorf("exit code must be a whole number from 0 to 255, got %v", num)
+		}
+		code = int(num)
+	}
+	return nil, &ExitError{Code: code}
+}
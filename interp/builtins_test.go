@@ -0,0 +1,130 @@
+package interp
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testInput runs tests like testRuns, each reading stdin from the start
+func testInput(t *testing.T, stdin string, tests []runTest) {
+	t.Helper()
+	for _, tt := range tests {
+		testRuns(t, []runTest{tt}, WithStdin(strings.NewReader(stdin)))
+	}
+}
+
+func TestPucho(t *testing.T) {
+	testInput(t, "Baburao\r\n 42 \nakhri", []runTest{
+		{name: "lines", src: `bol(pucho("naam? "), pucho(), pucho())`, out: "naam? Baburao\n 42 \nakhri\n"},
+		{name: "prompt of any type", src: `pucho(1 < 2)`, out: "true"},
+		{name: "end of input", src: `pucho()
+pucho()
+pucho()
+ye a = pucho()
+bol(a, pucho())`, out: "null\nnull\n"},
+		{name: "too many arguments", src: `pucho("a", "b")`, err: "line 1, column 1: pucho: expects at most 1 argument, got 2"},
+	})
+	testInput(t, "", []runTest{
+		{name: "no input", src: `bol(pucho("naam? "))`, out: "naam? null\n"},
+	})
+}
+
+func TestSankhya(t *testing.T) {
+	testInput(t, " 42 \n2.5\nbees\n", []runTest{
+		{name: "input", src: `bol(sankhya(pucho()) + sankhya(pucho()))`, out: "44.5\n"},
+		{name: "number", src: `bol(sankhya(7))`, out: "7\n"},
+		{name: "negative", src: `bol(sankhya("-3") + 1)`, out: "-2\n"},
+		{name: "bad input", src: `pucho()
+pucho()
+bol(sankhya(pucho()))`, err: `line 3, column 5: sankhya: cannot convert "bees" to a number`},
+		{name: "empty string", src: `sankhya("")`, err: `sankhya: cannot convert "" to a number`},
+		{name: "end of input", src: `pucho()
+pucho()
+pucho()
+sankhya(pucho())`, err: "sankhya: cannot convert null to a number"},
+		{name: "wrong type", src: `sankhya(1 < 2)`, err: "sankhya: cannot convert bool to a number"},
+		{name: "no argument", src: `sankhya()`, err: "sankhya expects 1 argument(s), got 0"},
+	})
+}
+
+func TestTarks(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "none", src: `bol(tarks, lambai(tarks))`, out: "[]\n0\n"},
+	})
+	testRuns(t, []runTest{
+		{name: "given", src: `bol(lambai(tarks), nikalo(tarks, 0), nikalo(tarks, 1) + 1)`, out: "2\n-v\n21\n"},
+		{name: "out of range", src: `nikalo(tarks, 2)`, err: "nikalo: index 2 out of range for list of length 2"},
+	}, WithArgs([]string{"-v", "2"}))
+}
+
+func TestMahaul(t *testing.T) {
+	env := WithEnv(func(name string) (string, bool) {
+		if name == "GHAR" {
+			return "/home/baburao", true
+		}
+		if name == "KHALI" {
+			return "", true
+		}
+		return "", false
+	})
+	testRuns(t, []runTest{
+		{name: "set", src: `bol(mahaul("GHAR"))`, out: "/home/baburao\n"},
+		{name: "empty", src: `bol("[{mahaul('KHALI')}]")`, out: "[]\n"},
+		{name: "not set", src: `bol(mahaul("NAHI"))`, out: "null\n"},
+		{name: "not a string", src: `mahaul(1)`, err: "mahaul: argument 1 must be a string, got number"},
+	}, env)
+}
+
+func TestBahar(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		out  string
+		code int
+	}{
+		{"code", "bol(1)\nbahar(3)\nbol(2)", "1\n", 3},
+		{"no code", "bahar()\nbol(2)", "", 0},
+		{"largest code", "bahar(255)", "", 255},
+		{"inside a function and loop", `firseKaro f() {
+	dohraye { bahar(7) }
+	bol("nahi")
+}
+f()
+bol("nahi")`, "", 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := run(t, tt.src)
+			var exit *ExitError
+			if !errors.As(err, &exit) {
+				t.Fatalf("error = %v, want an *ExitError", err)
+			}
+			if exit.Code != tt.code {
+				t.Errorf("exit code %d, want %d", exit.Code, tt.code)
+			}
+			if out != tt.out {
+				t.Errorf("printed %q, want %q", out, tt.out)
+			}
+
+			// Run returns the ExitError itself and reports nothing
+			var stderr strings.Builder
+			err = Run(tt.src, WithStdout(io.Discard), WithStderr(&stderr))
+			if exit, ok := err.(*ExitError); !ok || exit.Code != tt.code {
+				t.Errorf("Run returned %v, want exit status %d", err, tt.code)
+			}
+			if stderr.Len() > 0 {
+				t.Errorf("Run reported %q", stderr.String())
+			}
+		})
+	}
+
+	testRuns(t, []runTest{
+		{name: "code too large", src: `bahar(256)`, err: "bahar: exit code must be a whole number from 0 to 255, got 256"},
+		{name: "negative code", src: `bahar(0 - 1)`, err: "exit code must be a whole number from 0 to 255, got -1"},
+		{name: "fraction", src: `bahar(1.5)`, err: "exit code must be a whole number from 0 to 255, got 1.5"},
+		{name: "not a number", src: `bahar("1")`, err: "bahar: argument 1 must be a number, got string"},
+		{name: "two codes", src: `bahar(1, 2)`, err: "bahar: expects at most 1 argument, got 2"},
+	})
+}
@@ -0,0 +1,313 @@
+package interp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var runtimeValueType = reflect.TypeOf((*RuntimeValue)(nil)).Elem()
+
+// ToValue converts a Go value into a RuntimeValue:
+//
+//   - nil and nil pointers become NullValue
+//   - numbers of any Go numeric type become NumberValue
+//   - strings and bools become StringValue and BoolValue
+//   - slices and arrays become ListValue
+//   - maps and structs become RecordValue, with map keys in sorted order
+//     and struct fields in declaration order. Map keys are written with
+//     fmt.Sprint, two keys written the same, like 1 and "1", are an error.
+//   - a func with the RegisterFunc signature becomes a NativeFunction
+//
+// Only exported struct fields are converted. A `hlang:"naam"` tag renames
+// a field and `hlang:"-"` leaves it out. A value that contains itself,
+// like a tree whose nodes point back to their parent, is an error.
+func ToValue(v any) (RuntimeValue, error) {
+	switch x := v.(type) {
+	case nil:
+		return &NullValue{}, nil
+	case RuntimeValue:
+		return x, nil
+	case func(args []RuntimeValue) (RuntimeValue, error):
+		return &NativeFunction{Name: "<native>", Arity: -1, Fn: x}, nil
+	}
+	c := &converter{inside: map[reference]bool{}}
+	return c.toValue(reflect.ValueOf(v))
+}
+
+// converter keeps the pointers, maps and slices the value being converted
+// is inside of, meeting one of them again means the value contains itself
+type converter struct {
+	inside map[reference]bool
+}
+
+// reference is what a pointer, map or slice refers to. Slices of the
+// same array but of different lengths are different values.
+type reference struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter marks rv as being converted until the returned func is called,
+// or fails when it already is
+func (c *converter) enter(rv reflect.Value) (func(), error) {
+	ref := reference{ptr: rv.Pointer(), typ: rv.Type()}
+	if rv.Kind() == reflect.Slice {
+		ref.len = rv.Len()
+	}
+	if c.inside[ref] {
+		return nil, fmt.Errorf("cannot convert Go value of type %s, it contains itself", rv.Type())
+	}
+	c.inside[ref] = true
+	return func() { delete(c.inside, ref) }, nil
+}
+
+func (c *converter) toValue(rv reflect.Value) (RuntimeValue, error) {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return &NullValue{}, nil
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return &NullValue{}, nil
+		}
+		if rv.Type().Implements(runtimeValueType) {
+			return rv.Interface().(RuntimeValue), nil
+		}
+		if rv.Kind() == reflect.Pointer {
+			leave, err := c.enter(rv)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+		}
+		return c.toValue(rv.Elem())
+	case reflect.Bool:
+		return &BoolValue{Value: rv.Bool()}, nil
+	case reflect.String:
+		return &StringValue{Value: rv.String()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &NumberValue{Value: float64(rv.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &NumberValue{Value: float64(rv.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return &NumberValue{Value: rv.Float()}, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return &NullValue{}, nil
+		}
+		if rv.Kind() == reflect.Slice && rv.Len() > 0 {
+			leave, err := c.enter(rv)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+		}
+		list := &ListValue{Elements: make([]RuntimeValue, rv.Len())}
+		for idx := 0; idx < rv.Len(); idx++ {
+			el, err := c.toValue(rv.Index(idx))
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", idx, err)
+			}
+			list.Elements[idx] = el
+		}
+		return list, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return &NullValue{}, nil
+		}
+		leave, err := c.enter(rv)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		record := &RecordValue{Fields: make(map[string]RuntimeValue, rv.Len())}
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if _, ok := record.Fields[key]; ok {
+				return nil, fmt.Errorf("cannot convert Go value of type %s, more than one key is written %s", rv.Type(), key)
+			}
+			val, err := c.toValue(iter.Value())
+			if err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+			record.Keys = append(record.Keys, key)
+			record.Fields[key] = val
+		}
+		sort.Strings(record.Keys)
+		return record, nil
+	case reflect.Struct:
+		record := &RecordValue{Fields: map[string]RuntimeValue{}}
+		for _, field := range structFields(rv.Type()) {
+			val, err := c.toValue(rv.FieldByIndex(field.index))
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.name, err)
+			}
+			record.Keys = append(record.Keys, field.name)
+			record.Fields[field.name] = val
+		}
+		return record, nil
+	default:
+		return nil, fmt.Errorf("cannot convert Go value of type %s", rv.Type())
+	}
+}
+
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFields lists the exported fields of a struct type under their
+// script names
+func structFields(t reflect.Type) []structField {
+	fields := []structField{}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("hlang"); ok {
+			tag, _, _ = strings.Cut(tag, ",")
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, structField{name: name, index: field.Index})
+	}
+	return fields
+}
+
+// FromValue converts a RuntimeValue back into a plain Go value: float64,
+// string, bool, nil, []any or map[string]any. Functions are returned
+// unchanged. Use Decode to fill a typed Go value instead.
+func FromValue(v RuntimeValue) any {
+	if v == nil {
+		return nil
+	}
+	return v.GoValue()
+}
+
+// Decode stores v in the Go value target points to, the reverse of
+// ToValue. Records fill structs field by field using the same names and
+// tags ToValue does.
+func Decode(v RuntimeValue, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("decode target must be a non-nil pointer, got %T", target)
+	}
+	return decode(v, rv.Elem())
+}
+
+func decode(v RuntimeValue, out reflect.Value) error {
+	if out.Type().Implements(runtimeValueType) && reflect.TypeOf(v).AssignableTo(out.Type()) {
+		out.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	if _, ok := v.(*NullValue); ok {
+		out.SetZero()
+		return nil
+	}
+
+	switch out.Kind() {
+	case reflect.Interface:
+		if out.NumMethod() == 0 {
+			if goVal := v.GoValue(); goVal != nil {
+				out.Set(reflect.ValueOf(goVal))
+			}
+			return nil
+		}
+	case reflect.Pointer:
+		if out.IsNil() {
+			out.Set(reflect.New(out.Type().Elem()))
+		}
+		return decode(v, out.Elem())
+	case reflect.Bool:
+		if b, ok := v.(*BoolValue); ok {
+			out.SetBool(b.Value)
+			return nil
+		}
+	case reflect.String:
+		if s, ok := v.(*StringValue); ok {
+			out.SetString(s.Value)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := v.(*NumberValue); ok {
+			if n.Value != float64(int64(n.Value)) || out.OverflowInt(int64(n.Value)) {
+				return fmt.Errorf("number %v does not fit in %s", n.Value, out.Type())
+			}
+			out.SetInt(int64(n.Value))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, ok := v.(*NumberValue); ok {
+			if n.Value < 0 || n.Value != float64(uint64(n.Value)) || out.OverflowUint(uint64(n.Value)) {
+				return fmt.Errorf("number %v does not fit in %s", n.Value, out.Type())
+			}
+			out.SetUint(uint64(n.Value))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := v.(*NumberValue); ok {
+			out.SetFloat(n.Value)
+			return nil
+		}
+	case reflect.Slice:
+		if list, ok := v.(*ListValue); ok {
+			slice := reflect.MakeSlice(out.Type(), len(list.Elements), len(list.Elements))
+			for idx, el := range list.Elements {
+				if err := decode(el, slice.Index(idx)); err != nil {
+					return fmt.Errorf("index %d: %w", idx, err)
+				}
+			}
+			out.Set(slice)
+			return nil
+		}
+	case reflect.Array:
+		if list, ok := v.(*ListValue); ok {
+			if len(list.Elements) != out.Len() {
+				return fmt.Errorf("list of %d elements does not fit in %s", len(list.Elements), out.Type())
+			}
+			for idx, el := range list.Elements {
+				if err := decode(el, out.Index(idx)); err != nil {
+					return fmt.Errorf("index %d: %w", idx, err)
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if record, ok := v.(*RecordValue); ok && out.Type().Key().Kind() == reflect.String {
+			m := reflect.MakeMapWithSize(out.Type(), len(record.Fields))
+			for _, key := range record.Keys {
+				val := reflect.New(out.Type().Elem()).Elem()
+				if err := decode(record.Fields[key], val); err != nil {
+					return fmt.Errorf("key %s: %w", key, err)
+				}
+				m.SetMapIndex(reflect.ValueOf(key).Convert(out.Type().Key()), val)
+			}
+			out.Set(m)
+			return nil
+		}
+	case reflect.Struct:
+		if record, ok := v.(*RecordValue); ok {
+			for _, field := range structFields(out.Type()) {
+				val, ok := record.Fields[field.name]
+				if !ok {
+					continue
+				}
+				if err := decode(val, out.FieldByIndex(field.index)); err != nil {
+					return fmt.Errorf("field %s: %w", field.name, err)
+				}
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot store %s in Go value of type %s", v.Type(), out.Type())
+}
@@ -0,0 +1,124 @@
+package interp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type treeNode struct {
+	Name     string
+	Parent   *treeNode
+	Children []*treeNode
+}
+
+func TestToValueCycles(t *testing.T) {
+	root := &treeNode{Name: "root"}
+	root.Children = []*treeNode{{Name: "child", Parent: root}}
+
+	self := map[string]any{}
+	self["self"] = self
+
+	list := []any{1, nil}
+	list[1] = list
+
+	tests := []struct {
+		name  string
+		value any
+		err   string
+	}{
+		{"parent pointer", root, "field Children: index 0: field Parent: cannot convert Go value of type *interp.treeNode, it contains itself"},
+		{"map", self, "key self: cannot convert Go value of type map[string]interface {}, it contains itself"},
+		{"slice", list, "index 1: cannot convert Go value of type []interface {}, it contains itself"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ToValue(tt.value)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestToValueMapKeys(t *testing.T) {
+	val, err := ToValue(map[any]int{1: 1, "2": 2, true: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record := val.(*RecordValue); !reflect.DeepEqual(record.Keys, []string{"1", "2", "true"}) {
+		t.Errorf("keys = %q", record.Keys)
+	}
+
+	_, err = ToValue(map[any]int{1: 1, "1": 2})
+	want := "cannot convert Go value of type map[interface {}]int, more than one key is written 1"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
+
+func TestToValueShared(t *testing.T) {
+	// the same pointer twice is not a cycle
+	leaf := &treeNode{Name: "leaf"}
+	tree := &treeNode{Name: "root", Children: []*treeNode{leaf, leaf}}
+	shared := []int{1, 2}
+
+	val, err := ToValue(map[string]any{"tree": tree, "a": shared, "b": shared})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"a": []any{1.0, 2.0},
+		"b": []any{1.0, 2.0},
+		"tree": map[string]any{
+			"Name":   "root",
+			"Parent": nil,
+			"Children": []any{
+				map[string]any{"Name": "leaf", "Parent": nil, "Children": nil},
+				map[string]any{"Name": "leaf", "Parent": nil, "Children": nil},
+			},
+		},
+	}
+	if got := FromValue(val); !reflect.DeepEqual(got, want) {
+		t.Errorf("ToValue gave %#v, want %#v", got, want)
+	}
+}
+
+func TestScriptRunCyclicGlobal(t *testing.T) {
+	script, err := Compile("bol(tree)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := &treeNode{Name: "root"}
+	root.Parent = root
+	_, err = script.Run(context.Background(), map[string]any{"tree": root})
+	if err == nil || !strings.Contains(err.Error(), "contains itself") {
+		t.Errorf("error = %v, want one about the value containing itself", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type point struct {
+		X, Y int
+		Tag  string `hlang:"naam"`
+	}
+	val, err := ToValue(point{X: 1, Y: 2, Tag: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FromValue(val); !reflect.DeepEqual(got, map[string]any{"X": 1.0, "Y": 2.0, "naam": "a"}) {
+		t.Errorf("ToValue gave %#v", got)
+	}
+	var back point
+	if err := Decode(val, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back != (point{X: 1, Y: 2, Tag: "a"}) {
+		t.Errorf("Decode gave %+v", back)
+	}
+	var small int8
+	if err := Decode(&NumberValue{Value: 300}, &small); err == nil || err.Error() != "number 300 does not fit in int8" {
+		t.Errorf("Decode of 300 into an int8: error = %v", err)
+	}
+}
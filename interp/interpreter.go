@@ -0,0 +1,1063 @@
+// Package interp runs HindiScript programs. Run is the quickest way to
+// run source code, Compile parses it once for a Script that can be run
+// many times, and an Interpreter runs syntax trees directly and lets a
+// host call the program's functions.
+package interp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"github.com/suraj-9849/hindiLang.git/token"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RuntimeValue is a value a program works with. GoValue gives it back as
+// a plain Go value, see FromValue.
+type RuntimeValue interface {
+	Type() string
+	GoValue() any
+}
+
+// NumberValue is a number, all numbers are float64
+type NumberValue struct {
+	Value float64
+}
+
+func (n *NumberValue) Type() string { return "number" }
+func (n *NumberValue) GoValue() any { return n.Value }
+
+// StringValue is a string of text
+type StringValue struct {
+	Value string
+}
+
+func (s *StringValue) Type() string { return "string" }
+func (s *StringValue) GoValue() any { return s.Value }
+
+// BoolValue is true or false, the result of comparisons
+type BoolValue struct {
+	Value bool
+}
+
+func (b *BoolValue) Type() string { return "bool" }
+func (b *BoolValue) GoValue() any { return b.Value }
+
+// NullValue is the absence of a value, what a function without wapas
+// bhejo returns
+type NullValue struct{}
+
+func (n *NullValue) Type() string { return "null" }
+func (n *NullValue) GoValue() any { return nil }
+
+// ListValue is an ordered collection, made from Go slices and arrays
+type ListValue struct {
+	Elements []RuntimeValue
+}
+
+func (l *ListValue) Type() string { return "list" }
+func (l *ListValue) GoValue() any {
+	out := make([]any, len(l.Elements))
+	for idx, el := range l.Elements {
+		out[idx] = el.GoValue()
+	}
+	return out
+}
+
+// RecordValue is a set of named fields, made from Go structs and maps.
+// Keys keeps the fields in order for printing.
+type RecordValue struct {
+	Keys   []string
+	Fields map[string]RuntimeValue
+}
+
+func (r *RecordValue) Type() string { return "record" }
+func (r *RecordValue) GoValue() any {
+	out := make(map[string]any, len(r.Fields))
+	for key, val := range r.Fields {
+		out[key] = val.GoValue()
+	}
+	return out
+}
+
+// FunctionValue is a function declared by the program with firseKaro.
+// Env is the environment it was declared in, which its body sees.
+type FunctionValue struct {
+	Name       string
+	Parameters []ast.Parameter
+	ReturnType string
+	Body       []ast.Node
+	Env        *Environment
+}
+
+func (f *FunctionValue) Type() string { return "function" }
+func (f *FunctionValue) GoValue() any { return f }
+
+// Arity gives the fewest and the most arguments the function can be
+// called with, most is -1 when a rest parameter takes any number
+func (f *FunctionValue) Arity() (least, most int) {
+	for _, param := range f.Parameters {
+		if param.Rest {
+			return least, -1
+		}
+		if param.Default == nil {
+			least++
+		}
+		most++
+	}
+	return least, most
+}
+
+// ArityRange describes how many arguments a function takes, e.g. "2",
+// "1 to 3" or "at least 1", for the least and most of Arity
+func ArityRange(least, most int) string {
+	switch {
+	case most < 0:
+		return fmt.Sprintf("at least %d", least)
+	case least == most:
+		return strconv.Itoa(least)
+	default:
+		return fmt.Sprintf("%d to %d", least, most)
+	}
+}
+
+// Environment holds the variables of one scope, a function call gets
+// its own on top of the one the function was declared in. Names not
+// found in it are looked up in its parent.
+type Environment struct {
+	parent    *Environment
+	variables map[string]RuntimeValue
+
+	// types holds the annotation of variables declared "ye naam: type"
+	types map[string]string
+}
+
+// NewEnvironment creates an empty scope inside parent, nil for the
+// global scope
+func NewEnvironment(parent *Environment) *Environment {
+	return &Environment{
+		parent:    parent,
+		variables: make(map[string]RuntimeValue),
+	}
+}
+
+// Define creates the variable name in this scope, or replaces it along
+// with its type annotation, and returns value
+func (e *Environment) Define(name string, value RuntimeValue) RuntimeValue {
+	e.variables[name] = value
+	delete(e.types, name)
+	return value
+}
+
+// DefineTyped defines a variable that can only hold values of the given
+// type, which Set checks from then on
+func (e *Environment) DefineTyped(name, typ string, value RuntimeValue) (RuntimeValue, error) {
+	if !matchesType(value, typ) {
+		return nil, fmt.Errorf("%s is declared as %s, it cannot hold a %s", name, typ, value.Type())
+	}
+	e.Define(name, value)
+	if typ != "" && typ != "any" {
+		if e.types == nil {
+			e.types = map[string]string{}
+		}
+		e.types[name] = typ
+	}
+	return value, nil
+}
+
+// matchesType reports whether val may be stored where typ is declared, an
+// empty type is no annotation at all
+func matchesType(val RuntimeValue, typ string) bool {
+	return typ == "" || typ == "any" || val.Type() == typ
+}
+
+// Get gives the value of name in the nearest scope defining it
+func (e *Environment) Get(name string) (RuntimeValue, error) {
+	if val, ok := e.variables[name]; ok {
+		return val, nil
+	}
+	if e.parent != nil {
+		return e.parent.Get(name)
+	}
+	return nil, fmt.Errorf("undefined variable: %s", name)
+}
+
+// Names lists the variables defined directly in this environment, sorted
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.variables))
+	for name := range e.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Set changes the variable name in the nearest scope defining it. It
+// fails for a name that is not defined or a value its annotation rules
+// out.
+func (e *Environment) Set(name string, value RuntimeValue) error {
+	if _, ok := e.variables[name]; ok {
+		if typ := e.types[name]; !matchesType(value, typ) {
+			return fmt.Errorf("%s is declared as %s, it cannot hold a %s", name, typ, value.Type())
+		}
+		e.variables[name] = value
+		return nil
+	}
+	if e.parent != nil {
+		return e.parent.Set(name, value)
+	}
+	return fmt.Errorf("cannot assign to undefined variable: %s", name)
+}
+
+// controlFlow is a roko, aage badho or wapas bhejo on its way to the loop
+// or call it ends
+type controlFlow struct {
+	Type  string // "break", "continue", "return"
+	Value RuntimeValue
+}
+
+// StackFrame is one active call: the function and where it was called
+// from. The bottom frame is the program itself, named "<main>".
+type StackFrame struct {
+	Function string
+	CallSite token.Position
+}
+
+// ErrStackOverflow is wrapped by the RuntimeError raised when calls nest
+// deeper than the interpreter's maximum call depth
+var ErrStackOverflow = errors.New("stack overflow")
+
+// ErrStepLimit is wrapped by the RuntimeError raised when a program uses
+// up the step budget given with WithStepLimit
+var ErrStepLimit = errors.New("step limit exceeded")
+
+// ErrMemoryLimit is wrapped by the RuntimeError raised when a program's
+// values allocate more than the limit given with WithMemoryLimit
+var ErrMemoryLimit = errors.New("memory limit exceeded")
+
+// IsLimitError reports whether err stopped a program because it ran out
+// of a budget the host gave it: the step or memory limit, or the deadline
+// of its context. Running out of call depth is a runtime error like any
+// other, as it is mostly caused by recursion that never ends.
+func IsLimitError(err error) bool {
+	return errors.Is(err, ErrStepLimit) || errors.Is(err, ErrMemoryLimit) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// DefaultMaxCallDepth is deep enough for real recursion. Go's own stack
+// is protected by maxNesting, however deep calls are allowed to go.
+const DefaultMaxCallDepth = 50000
+
+// maxNesting is how many Evaluate calls may be active at once. Each takes
+// up to about 600 bytes of Go stack, so this stays near 150MB, below the
+// limit Go puts on a goroutine's stack, even when every call is made
+// from deep inside nested blocks.
+const maxNesting = 250000
+
+// RuntimeError is an error raised while running a program, with the
+// position it happened at and the call stack at that moment. Err is the
+// underlying error, so errors.Is(err, ErrStackOverflow) and the like work.
+type RuntimeError struct {
+	Message string
+	Pos     token.Position
+	Stack   []StackFrame
+	Err     error
+}
+
+// Error is the message after the position, errors raised outside the
+// program, like by a call from Go, have no position and only the message
+func (e *RuntimeError) Error() string {
+	if e.Pos.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+func (e *RuntimeError) Unwrap() error {
+	return e.Err
+}
+
+// Traceback formats the call stack like Python does, innermost call last,
+// quoting the lines of source when it is given
+func (e *RuntimeError) Traceback(source string) string {
+	lines := strings.Split(source, "\n")
+	var sb strings.Builder
+	sb.WriteString("Traceback (most recent call last):\n")
+
+	// deep recursion repeats the same frame thousands of times, so like
+	// Python only the first few repeats are printed
+	const maxRepeats = 3
+	var last string
+	repeats := 0
+	flush := func() {
+		if repeats >= maxRepeats {
+			fmt.Fprintf(&sb, "  [previous line repeated %d more times]\n", repeats-maxRepeats+1)
+		}
+	}
+
+	for idx, frame := range e.Stack {
+		// a frame is at the call into the next frame, the last one is
+		// where the error happened
+		pos := e.Pos
+		if idx+1 < len(e.Stack) {
+			pos = e.Stack[idx+1].CallSite
+		}
+
+		entry := fmt.Sprintf("  line %d, in %s\n", pos.Line, frame.Function)
+		if pos.Line == 0 {
+			// the next frame was called from Go
+			entry = fmt.Sprintf("  in %s\n", frame.Function)
+		}
+		if pos.Line >= 1 && pos.Line <= len(lines) {
+			if line := strings.TrimSpace(lines[pos.Line-1]); line != "" {
+				entry += fmt.Sprintf("    %s\n", line)
+			}
+		}
+
+		if entry == last {
+			repeats++
+			if repeats >= maxRepeats {
+				continue
+			}
+		} else {
+			flush()
+			last = entry
+			repeats = 0
+		}
+		sb.WriteString(entry)
+	}
+	flush()
+	return sb.String()
+}
+
+// Interpreter runs syntax trees and keeps the state between them: the
+// global variables, the call stack and the limits it was given. Create
+// it with NewInterpreter. It must not be used from several goroutines at
+// once.
+type Interpreter struct {
+	env          *Environment
+	controlFlow  *controlFlow
+	callStack    []StackFrame
+	maxCallDepth int
+	nesting      int // active Evaluate calls, see maxNesting
+
+	// a step is one loop iteration or one function call, the points where
+	// a program can run forever
+	ctx      context.Context
+	maxSteps int
+	steps    int
+
+	// allocated is an estimate of the bytes taken by every value the
+	// program has built, it never goes down
+	maxMemory int
+	allocated int
+
+	stdout io.Writer
+	stderr io.Writer
+	stdin  io.Reader
+	input  *bufio.Reader // wraps stdin once, so buffered input isn't lost between reads
+
+	lookupEnv func(string) (string, bool)
+}
+
+// Option configures an Interpreter in NewInterpreter
+type Option func(*Interpreter)
+
+// WithMaxCallDepth limits how deeply function calls may nest before the
+// program fails with ErrStackOverflow. A program whose calls and blocks
+// together nest too deeply for Go's stack fails the same way sooner.
+func WithMaxCallDepth(depth int) Option {
+	return func(i *Interpreter) {
+		i.maxCallDepth = depth
+	}
+}
+
+// WithContext makes the program stop with the context's error once the
+// context is cancelled or its deadline passes
+func WithContext(ctx context.Context) Option {
+	return func(i *Interpreter) {
+		i.ctx = ctx
+	}
+}
+
+// WithStepLimit stops the program with ErrStepLimit after it has run the
+// given number of loop iterations and function calls. Zero means no limit.
+func WithStepLimit(steps int) Option {
+	return func(i *Interpreter) {
+		i.maxSteps = steps
+	}
+}
+
+// WithMemoryLimit stops the program with ErrMemoryLimit once the values
+// it builds add up to more than the given number of bytes. Zero means no
+// limit.
+func WithMemoryLimit(bytes int) Option {
+	return func(i *Interpreter) {
+		i.maxMemory = bytes
+	}
+}
+
+// WithStdout sends the output of bol and the other builtins to w
+func WithStdout(w io.Writer) Option {
+	return func(i *Interpreter) {
+		i.stdout = w
+	}
+}
+
+// WithStderr sends error reports to w
+func WithStderr(w io.Writer) Option {
+	return func(i *Interpreter) {
+		i.stderr = w
+	}
+}
+
+// WithStdin makes the builtins that read input read from r
+func WithStdin(r io.Reader) Option {
+	return func(i *Interpreter) {
+		i.stdin = r
+	}
+}
+
+// NewInterpreter creates an interpreter with the builtins defined,
+// reading os.Stdin and writing os.Stdout unless opts say otherwise
+func NewInterpreter(opts ...Option) *Interpreter {
+	env := NewEnvironment(nil)
+
+	interpreter := &Interpreter{
+		env:          env,
+		controlFlow:  nil,
+		callStack:    []StackFrame{{Function: "<main>"}},
+		maxCallDepth: DefaultMaxCallDepth,
+		ctx:          context.Background(),
+		stdout:       os.Stdout,
+		stderr:       os.Stderr,
+		stdin:        os.Stdin,
+		lookupEnv:    os.LookupEnv,
+	}
+	interpreter.registerBuiltins()
+	for _, opt := range opts {
+		opt(interpreter)
+	}
+	return interpreter
+}
+
+// Evaluate runs a node. The first node an error passes through turns it
+// into a *RuntimeError carrying that node's position and the call stack.
+func (i *Interpreter) Evaluate(node ast.Node) (RuntimeValue, error) {
+	var val RuntimeValue
+	var err error
+	if i.nesting >= maxNesting {
+		err = &causeError{"stack overflow: the program nests too deeply", ErrStackOverflow}
+	} else {
+		i.nesting++
+		val, err = i.evaluate(node)
+		i.nesting--
+	}
+	if err != nil {
+		if _, ok := err.(*RuntimeError); !ok {
+			rtErr := i.runtimeError(node.Position(), err.Error())
+			rtErr.Err = err
+			err = rtErr
+		}
+		return nil, err
+	}
+	return val, nil
+}
+
+// causeError is an error with its own message that still matches its
+// cause with errors.Is
+type causeError struct {
+	message string
+	cause   error
+}
+
+func (e *causeError) Error() string { return e.message }
+func (e *causeError) Unwrap() error { return e.cause }
+
+func (i *Interpreter) runtimeError(pos token.Position, message string) *RuntimeError {
+	stack := make([]StackFrame, len(i.callStack))
+	copy(stack, i.callStack)
+	return &RuntimeError{Message: message, Pos: pos, Stack: stack}
+}
+
+// step is called on every loop iteration and function call to enforce the
+// step budget and notice cancellation
+func (i *Interpreter) step() error {
+	i.steps++
+	if i.maxSteps > 0 && i.steps > i.maxSteps {
+		return &causeError{fmt.Sprintf("step limit of %d exceeded", i.maxSteps), ErrStepLimit}
+	}
+	select {
+	case <-i.ctx.Done():
+		return &causeError{fmt.Sprintf("execution stopped: %v", i.ctx.Err()), i.ctx.Err()}
+	default:
+		return nil
+	}
+}
+
+// elementSize is what allocate counts for one element of a list, the
+// interface value holding it
+const elementSize = 16
+
+// allocate accounts for a value of the given size before it is built, so
+// a runaway string or list fails here instead of exhausting the host's memory
+func (i *Interpreter) allocate(bytes int) error {
+	i.allocated += bytes
+	if i.maxMemory > 0 && i.allocated > i.maxMemory {
+		return &causeError{fmt.Sprintf("memory limit of %d bytes exceeded", i.maxMemory), ErrMemoryLimit}
+	}
+	return nil
+}
+
+func (i *Interpreter) evaluate(node ast.Node) (RuntimeValue, error) {
+	if i.controlFlow != nil {
+		return &NullValue{}, nil
+	}
+
+	if node == nil {
+		return &NullValue{}, nil
+	}
+
+	switch n := node.(type) {
+	case *ast.Program:
+		return i.evalProgram(n)
+	case *ast.Declaration:
+		return i.evalDeclaration(n)
+	case *ast.Assignment:
+		return i.evalAssignment(n)
+	case *ast.Identifier:
+		return i.evalIdentifier(n)
+	case *ast.Literal:
+		return i.evalLiteral(n)
+	case *ast.TemplateLiteral:
+		return i.evalTemplateLiteral(n)
+	case *ast.BinaryExpression:
+		return i.evalBinaryExpression(n)
+	case *ast.FunctionDeclaration:
+		return i.evalFunctionDeclaration(n)
+	case *ast.FunctionCall:
+		return i.evalFunctionCall(n)
+	case *ast.IfStatement:
+		return i.evalIfStatement(n)
+	case *ast.WhileLoop:
+		return i.evalWhileLoop(n)
+	case *ast.RepeatLoop:
+		return i.evalRepeatLoop(n)
+	case *ast.BreakStatement:
+		i.controlFlow = &controlFlow{Type: "break"}
+		return &NullValue{}, nil
+	case *ast.ContinueStatement:
+		i.controlFlow = &controlFlow{Type: "continue"}
+		return &NullValue{}, nil
+	case *ast.ReturnStatement:
+		val, err := i.Evaluate(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		i.controlFlow = &controlFlow{Type: "return", Value: val}
+		return val, nil
+	default:
+		return nil, fmt.Errorf("unknown node type: %s", node.NodeType())
+	}
+}
+
+func (i *Interpreter) evalProgram(p *ast.Program) (RuntimeValue, error) {
+	var lastValue RuntimeValue = &NullValue{}
+	for _, node := range p.Body {
+		val, err := i.Evaluate(node)
+		if err != nil {
+			return nil, err
+		}
+		lastValue = val
+		if i.controlFlow != nil && i.controlFlow.Type == "return" {
+			break
+		}
+	}
+	return lastValue, nil
+}
+
+func (i *Interpreter) evalDeclaration(d *ast.Declaration) (RuntimeValue, error) {
+	value, err := i.Evaluate(d.Value)
+	if err != nil {
+		return nil, err
+	}
+	return i.env.DefineTyped(d.Name, d.Type, value)
+}
+
+func (i *Interpreter) evalAssignment(a *ast.Assignment) (RuntimeValue, error) {
+	value, err := i.Evaluate(a.Value)
+	if err != nil {
+		return nil, err
+	}
+	err = i.env.Set(a.Name, value)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (i *Interpreter) evalIdentifier(id *ast.Identifier) (RuntimeValue, error) {
+	return i.env.Get(id.Name)
+}
+
+func (i *Interpreter) evalLiteral(l *ast.Literal) (RuntimeValue, error) {
+	if l.IsNumber() {
+		num, err := strconv.ParseFloat(l.Value, 64)
+		if err != nil {
+			return nil, i.runtimeError(l.Pos, fmt.Sprintf("invalid number %q", l.Value))
+		}
+		return &NumberValue{Value: num}, nil
+	}
+	return &StringValue{Value: l.Value}, nil
+}
+
+func (i *Interpreter) evalTemplateLiteral(t *ast.TemplateLiteral) (RuntimeValue, error) {
+	parts := make([]string, 0, len(t.Quasis)+len(t.Expressions))
+	size := 0
+	for idx, expr := range t.Expressions {
+		val, err := i.Evaluate(expr)
+		if err != nil {
+			return nil, err
+		}
+		str := i.toString(val)
+		parts = append(parts, t.Quasis[idx], str)
+		size += len(t.Quasis[idx]) + len(str)
+	}
+	parts = append(parts, t.Quasis[len(t.Quasis)-1])
+	size += len(t.Quasis[len(t.Quasis)-1])
+
+	if err := i.allocate(size); err != nil {
+		return nil, err
+	}
+	return &StringValue{Value: strings.Join(parts, "")}, nil
+}
+
+func (i *Interpreter) evalBinaryExpression(b *ast.BinaryExpression) (RuntimeValue, error) {
+	left, err := i.Evaluate(b.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := i.Evaluate(b.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	leftNum, leftIsNum := left.(*NumberValue)
+	rightNum, rightIsNum := right.(*NumberValue)
+
+	if leftIsNum && rightIsNum {
+		switch b.Operator {
+		case "+":
+			return &NumberValue{Value: leftNum.Value + rightNum.Value}, nil
+		case "-":
+			return &NumberValue{Value: leftNum.Value - rightNum.Value}, nil
+		case "*":
+			return &NumberValue{Value: leftNum.Value * rightNum.Value}, nil
+		case "/":
+			if rightNum.Value == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return &NumberValue{Value: leftNum.Value / rightNum.Value}, nil
+		case "%":
+			if rightNum.Value == 0 {
+				return nil, fmt.Errorf("modulo by zero")
+			}
+			leftInt := int64(leftNum.Value)
+			rightInt := int64(rightNum.Value)
+			return &NumberValue{Value: float64(leftInt % rightInt)}, nil
+		case "<":
+			return &BoolValue{Value: leftNum.Value < rightNum.Value}, nil
+		case ">":
+			return &BoolValue{Value: leftNum.Value > rightNum.Value}, nil
+		case "<=":
+			return &BoolValue{Value: leftNum.Value <= rightNum.Value}, nil
+		case ">=":
+			return &BoolValue{Value: leftNum.Value >= rightNum.Value}, nil
+		case "==":
+			return &BoolValue{Value: leftNum.Value == rightNum.Value}, nil
+		case "!=":
+			return &BoolValue{Value: leftNum.Value != rightNum.Value}, nil
+		}
+	}
+
+	// String concatenation
+	if b.Operator == "+" {
+		leftStr := i.toString(left)
+		rightStr := i.toString(right)
+		if err := i.allocate(len(leftStr) + len(rightStr)); err != nil {
+			return nil, err
+		}
+		return &StringValue{Value: leftStr + rightStr}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported operator: %s", b.Operator)
+}
+
+func (i *Interpreter) evalFunctionDeclaration(f *ast.FunctionDeclaration) (RuntimeValue, error) {
+	fn := &FunctionValue{
+		Name:       f.Name,
+		Parameters: f.Parameters,
+		ReturnType: f.ReturnType,
+		Body:       f.Body,
+		Env:        i.env,
+	}
+	return i.env.Define(f.Name, fn), nil
+}
+
+func (i *Interpreter) evalFunctionCall(f *ast.FunctionCall) (RuntimeValue, error) {
+	// Get function from environment
+	fnVal, err := i.env.Get(f.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch fnVal.(type) {
+	case *NativeFunction, *FunctionValue:
+	default:
+		return nil, fmt.Errorf("%s is not a function", f.Name)
+	}
+
+	// Evaluate arguments
+	args := make([]RuntimeValue, len(f.Arguments))
+	for idx, arg := range f.Arguments {
+		val, err := i.Evaluate(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[idx] = val
+	}
+
+	return i.callValue(fnVal, args, f.Pos)
+}
+
+// callValue calls a function value with already evaluated arguments.
+// callSite is where the call happened, the zero Position for calls made
+// from Go.
+func (i *Interpreter) callValue(fnVal RuntimeValue, args []RuntimeValue, callSite token.Position) (RuntimeValue, error) {
+	if native, ok := fnVal.(*NativeFunction); ok {
+		return i.callNative(native, args)
+	}
+
+	fn, ok := fnVal.(*FunctionValue)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a function", fnVal.Type())
+	}
+
+	if err := i.step(); err != nil {
+		return nil, err
+	}
+
+	// the bottom frame is <main>, so the stack holds one more than the depth
+	if len(i.callStack) > i.maxCallDepth {
+		return nil, &causeError{fmt.Sprintf("stack overflow: maximum call depth of %d exceeded", i.maxCallDepth), ErrStackOverflow}
+	}
+
+	if least, most := fn.Arity(); len(args) < least || (most >= 0 && len(args) > most) {
+		return nil, fmt.Errorf("%s expects %s argument(s), got %d", fn.Name, ArityRange(least, most), len(args))
+	}
+
+	// Save current environment and control flow
+	prevEnv := i.env
+	prevFlow := i.controlFlow
+	i.env = NewEnvironment(fn.Env)
+	i.controlFlow = nil
+	i.callStack = append(i.callStack, StackFrame{Function: fn.Name, CallSite: callSite})
+
+	// Execute function body, once the parameters are bound
+	var result RuntimeValue = &NullValue{}
+	err := i.bindParameters(fn, args)
+	if err == nil {
+		for _, node := range fn.Body {
+			var val RuntimeValue
+			if val, err = i.Evaluate(node); err != nil {
+				break
+			}
+			result = val
+			if i.controlFlow != nil && i.controlFlow.Type == "return" {
+				result = i.controlFlow.Value
+				break
+			}
+		}
+	}
+
+	// Restore environment and control flow
+	i.env = prevEnv
+	i.controlFlow = prevFlow
+	i.callStack = i.callStack[:len(i.callStack)-1]
+
+	if err != nil {
+		return nil, err
+	}
+	if !matchesType(result, fn.ReturnType) {
+		return nil, fmt.Errorf("%s must return a %s, got %s", fn.Name, fn.ReturnType, result.Type())
+	}
+	return result, nil
+}
+
+// bindParameters defines the parameters of fn in the current environment,
+// checking their type annotations. Defaults are evaluated there too, so
+// they can use the parameters before them.
+func (i *Interpreter) bindParameters(fn *FunctionValue, args []RuntimeValue) error {
+	for idx, param := range fn.Parameters {
+		if param.Rest {
+			rest := &ListValue{Elements: []RuntimeValue{}}
+			if idx < len(args) {
+				if err := i.allocate((len(args) - idx) * elementSize); err != nil {
+					return err
+				}
+				rest.Elements = append(rest.Elements, args[idx:]...)
+			}
+			for n, arg := range rest.Elements {
+				if !matchesType(arg, param.Type) {
+					return fmt.Errorf("%s: argument %d must be a %s, got %s", fn.Name, idx+n+1, param.Type, arg.Type())
+				}
+			}
+			i.env.Define(param.Name, rest)
+			continue
+		}
+
+		if idx < len(args) {
+			if !matchesType(args[idx], param.Type) {
+				return fmt.Errorf("%s: argument %d must be a %s, got %s", fn.Name, idx+1, param.Type, args[idx].Type())
+			}
+			i.env.DefineTyped(param.Name, param.Type, args[idx])
+			continue
+		}
+
+		val, err := i.Evaluate(param.Default)
+		if err != nil {
+			return err
+		}
+		if _, err := i.env.DefineTyped(param.Name, param.Type, val); err != nil {
+			return fmt.Errorf("%s: %w", fn.Name, err)
+		}
+	}
+	return nil
+}
+
+func (i *Interpreter) evalIfStatement(ifStmt *ast.IfStatement) (RuntimeValue, error) {
+	condition, err := i.Evaluate(ifStmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.isTruthy(condition) {
+		return i.evalBlock(ifStmt.Consequent)
+	}
+
+	// Check else-if statements
+	for _, elseIf := range ifStmt.ElseIfs {
+		cond, err := i.Evaluate(elseIf.Condition)
+		if err != nil {
+			return nil, err
+		}
+		if i.isTruthy(cond) {
+			return i.evalBlock(elseIf.Consequent)
+		}
+	}
+
+	// Execute else block
+	if len(ifStmt.Alternate) > 0 {
+		return i.evalBlock(ifStmt.Alternate)
+	}
+
+	return &NullValue{}, nil
+}
+
+func (i *Interpreter) evalWhileLoop(w *ast.WhileLoop) (RuntimeValue, error) {
+	var lastValue RuntimeValue = &NullValue{}
+
+	for {
+		if err := i.step(); err != nil {
+			return nil, err
+		}
+
+		condition, err := i.Evaluate(w.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if !i.isTruthy(condition) {
+			break
+		}
+
+		lastValue, err = i.evalBlock(w.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if i.controlFlow != nil {
+			if i.controlFlow.Type == "break" {
+				i.controlFlow = nil
+				break
+			}
+			if i.controlFlow.Type == "continue" {
+				i.controlFlow = nil
+				continue
+			}
+			if i.controlFlow.Type == "return" {
+				break
+			}
+		}
+	}
+
+	return lastValue, nil
+}
+
+func (i *Interpreter) evalRepeatLoop(r *ast.RepeatLoop) (RuntimeValue, error) {
+	var lastValue RuntimeValue = &NullValue{}
+
+	for {
+		if err := i.step(); err != nil {
+			return nil, err
+		}
+
+		val, err := i.evalBlock(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		lastValue = val
+
+		if i.controlFlow != nil {
+			if i.controlFlow.Type == "break" {
+				i.controlFlow = nil
+				break
+			}
+			if i.controlFlow.Type == "continue" {
+				i.controlFlow = nil
+				continue
+			}
+			if i.controlFlow.Type == "return" {
+				break
+			}
+		}
+	}
+
+	return lastValue, nil
+}
+
+func (i *Interpreter) evalBlock(nodes []ast.Node) (RuntimeValue, error) {
+	var lastValue RuntimeValue = &NullValue{}
+	for _, node := range nodes {
+		val, err := i.Evaluate(node)
+		if err != nil {
+			return nil, err
+		}
+		lastValue = val
+		if i.controlFlow != nil {
+			break
+		}
+	}
+	return lastValue, nil
+}
+
+func (i *Interpreter) isTruthy(val RuntimeValue) bool {
+	switch v := val.(type) {
+	case *BoolValue:
+		return v.Value
+	case *NumberValue:
+		return v.Value != 0
+	case *StringValue:
+		return v.Value != ""
+	case *NullValue:
+		return false
+	case *ListValue:
+		return len(v.Elements) > 0
+	default:
+		return true
+	}
+}
+
+// inspect is toString for values inside collections, where strings are
+// quoted so [1, "1"] can be told apart
+func (i *Interpreter) inspect(val RuntimeValue) string {
+	if str, ok := val.(*StringValue); ok {
+		return strconv.Quote(str.Value)
+	}
+	return i.toString(val)
+}
+
+func (i *Interpreter) toString(val RuntimeValue) string {
+	switch v := val.(type) {
+	case *NumberValue:
+		if v.Value == float64(int(v.Value)) {
+			return fmt.Sprintf("%d", int(v.Value))
+		}
+		return fmt.Sprintf("%v", v.Value)
+	case *StringValue:
+		return v.Value
+	case *BoolValue:
+		return fmt.Sprintf("%v", v.Value)
+	case *NullValue:
+		return "null"
+	case *FunctionValue:
+		return "<function>"
+	case *NativeFunction:
+		return "<native function " + v.Name + ">"
+	case *ListValue:
+		parts := make([]string, len(v.Elements))
+		for idx, el := range v.Elements {
+			parts[idx] = i.inspect(el)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case *RecordValue:
+		parts := make([]string, len(v.Keys))
+		for idx, key := range v.Keys {
+			parts[idx] = key + ": " + i.inspect(v.Fields[key])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// Run lexes, parses and runs a program, printing any errors to the
+// interpreter's stderr. The options configure the interpreter, e.g. its
+// limits and I/O.
+//
+// The error returned tells what went wrong: lexer.SyntaxErrors when the
+// program does not parse and nothing was run, *ExitError when it called
+// bahar(), and otherwise a *RuntimeError, for which IsLimitError tells
+// whether the program was stopped by one of its limits.
+func Run(code string, opts ...Option) error {
+	interpreter := NewInterpreter(opts...)
+
+	program, err := parser.Parse(code)
+	if err != nil {
+		for _, e := range err.(lexer.SyntaxErrors) {
+			fmt.Fprintf(interpreter.stderr, "Syntax Error: %v\n", e)
+		}
+		return err
+	}
+
+	_, err = interpreter.Evaluate(program)
+	if err == nil {
+		return nil
+	}
+	// bahar() ending the program is not a failure to report
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return exit
+	}
+
+	prefix := "Runtime Error"
+	if IsLimitError(err) {
+		prefix = "Limit Error"
+	}
+	if rtErr, ok := err.(*RuntimeError); ok {
+		fmt.Fprint(interpreter.stderr, rtErr.Traceback(code))
+		fmt.Fprintf(interpreter.stderr, "%s: %s\n", prefix, rtErr.Message)
+		return err
+	}
+	fmt.Fprintf(interpreter.stderr, "%s: %v\n", prefix, err)
+	return err
+}
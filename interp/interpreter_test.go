@@ -0,0 +1,486 @@
+package interp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// run runs src with opts and returns what it printed and the error it
+// stopped with
+func run(t *testing.T, src string, opts ...Option) (string, error) {
+	t.Helper()
+	program, err := parser.Parse(src)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", src, err)
+	}
+	var out bytes.Buffer
+	_, err = NewInterpreter(append([]Option{WithStdout(&out), WithStderr(io.Discard)}, opts...)...).Run(program)
+	return out.String(), err
+}
+
+type runTest struct {
+	name string
+	src  string
+	out  string
+	err  string // what the error message holds, "" when there is none
+}
+
+func testRuns(t *testing.T, tests []runTest, opts ...Option) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := run(t, tt.src, opts...)
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("error = %v, want one holding %q", err, tt.err)
+			}
+			if out != tt.out {
+				t.Errorf("printed %q, want %q", out, tt.out)
+			}
+		})
+	}
+}
+
+func TestLiterals(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "number", src: "bol(12 + 1)", out: "13\n"},
+		{name: "string", src: `bol("12" + 1)`, out: "121\n"},
+		{name: "numeric string declared string", src: `ye s: string = "123"
+bol(s)`, out: "123\n"},
+		{name: "numeric string argument", src: `firseKaro f(a: string) { bol(a) }
+f("42")`, out: "42\n"},
+		{name: "numeric string declared number", src: `ye n: number = "5"`,
+			err: "n is declared as number, it cannot hold a string"},
+		{name: "number declared string", src: `ye s: string = 123`,
+			err: "s is declared as string, it cannot hold a number"},
+	})
+}
+
+func TestArity(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "exact", src: `firseKaro f(a, b) { bol(a + b) }
+f(1, 2)`, out: "3\n"},
+		{name: "too few", src: `firseKaro f(a, b) { bol(a + b) }
+f(1)`, err: "line 2, column 1: f expects 2 argument(s), got 1"},
+		{name: "too many", src: `firseKaro f(a) { bol(a) }
+f(1, 2)`, err: "f expects 1 argument(s), got 2"},
+		{name: "none", src: `firseKaro f() { bol(1) }
+f(1)`, err: "f expects 0 argument(s), got 1"},
+		{name: "arguments are evaluated first", src: `firseKaro f(a) { bol(a) }
+f(bol("x"), 2)`, out: "x\n", err: "f expects 1 argument(s), got 2"},
+		{name: "builtin", src: `bol(lambai("a", "b"))`, err: "lambai expects 1 argument(s), got 2"},
+		{name: "not a function", src: `ye f = 1
+f()`, err: "f is not a function"},
+	})
+}
+
+func TestDefaultParameters(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "used", src: `firseKaro f(a, b = 2) { bol(a + b) }
+f(1)`, out: "3\n"},
+		{name: "given", src: `firseKaro f(a, b = 2) { bol(a + b) }
+f(1, 5)`, out: "6\n"},
+		{name: "earlier parameter", src: `firseKaro f(a, b = a * 2) { bol(b) }
+f(4)`, out: "8\n"},
+		{name: "evaluated each call", src: `ye n = 0
+firseKaro next() { n = n + 1
+wapas bhejo n }
+firseKaro f(a = next()) { bol(a) }
+f()
+f()
+f(9)`, out: "1\n2\n9\n"},
+		{name: "too few", src: `firseKaro f(a, b = 2) { bol(a) }
+f()`, err: "f expects 1 to 2 argument(s), got 0"},
+		{name: "too many", src: `firseKaro f(a, b = 2) { bol(a) }
+f(1, 2, 3)`, err: "f expects 1 to 2 argument(s), got 3"},
+		{name: "typed default", src: `firseKaro f(a: number = "x") { bol(a) }
+f()`, err: "f: a is declared as number, it cannot hold a string"},
+		{name: "failing default", src: `firseKaro f(a = 1 / 0) { bol(a) }
+f()`, err: "division by zero"},
+	})
+}
+
+func TestRestParameters(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "collected", src: `firseKaro f(a, ...baaki) { bol(a, lambai(baaki)) }
+f(1, 2, 3)`, out: "1\n2\n"},
+		{name: "empty", src: `firseKaro f(a, ...baaki) { bol(lambai(baaki)) }
+f(1)`, out: "0\n"},
+		{name: "elements", src: `firseKaro f(...baaki) { bol(nikalo(baaki, 1)) }
+f("a", "b")`, out: "b\n"},
+		{name: "after a default", src: `firseKaro f(a = 1, ...baaki) { bol(a, lambai(baaki)) }
+f()
+f(5, 6)`, out: "1\n0\n5\n1\n"},
+		{name: "too few", src: `firseKaro f(a, b, ...baaki) { bol(a) }
+f(1)`, err: "f expects at least 2 argument(s), got 1"},
+		{name: "typed", src: `firseKaro f(a, ...baaki: number) { bol(a) }
+f("x", 1, "y")`, err: "f: argument 3 must be a number, got string"},
+	})
+}
+
+func TestTypeErrors(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "declaration", src: `ye s: string = 1`,
+			err: "line 1, column 1: s is declared as string, it cannot hold a number"},
+		{name: "assignment", src: `ye n: number = 1
+n = "x"`, err: "line 2, column 1: n is declared as number, it cannot hold a string"},
+		{name: "any", src: `ye a: any = 1
+a = "x"
+bol(a)`, out: "x\n"},
+		{name: "argument", src: `firseKaro f(a: number) { bol(a) }
+f("x")`, err: "f: argument 1 must be a number, got string"},
+		{name: "parameter assignment", src: `firseKaro f(a: number) { a = "x" }
+f(1)`, err: "a is declared as number, it cannot hold a string"},
+		{name: "return", src: `firseKaro f(): number { wapas bhejo "x" }
+f()`, err: "f must return a number, got string"},
+		{name: "missing return", src: `firseKaro f(): string { bol(1) }
+f()`, out: "1\n", err: "f must return a string, got null"},
+		{name: "return type kept", src: `firseKaro f(): string { wapas bhejo "a" }
+bol(f())`, out: "a\n"},
+		{name: "not a function", src: `ye n = 1
+n()`, err: "n is not a function"},
+		{name: "division by zero", src: `bol(1 / 0)`, err: "division by zero"},
+		{name: "modulo by zero", src: `bol(1 % 0)`, err: "modulo by zero"},
+		{name: "operator on strings", src: `bol("a" - 1)`, err: "unsupported operator: -"},
+		{name: "comparing strings", src: `bol("a" < "b")`, err: "unsupported operator: <"},
+		{name: "builtin argument", src: `bol(nikalo(1, 0))`, err: "nikalo: cannot take an element of number"},
+	})
+}
+
+func TestCallDepth(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "deep recursion", src: `firseKaro f(n) {
+	agar n == 0 { wapas bhejo 0 }
+	wapas bhejo 1 + f(n - 1)
+}
+bol(f(10000))`, out: "10000\n"},
+		{name: "infinite recursion", src: `firseKaro f(n) { wapas bhejo f(n + 1) }
+f(0)`, err: "stack overflow: maximum call depth of 50000 exceeded"},
+	})
+}
+
+func TestStackOverflow(t *testing.T) {
+	// recursing from inside nested blocks takes much more of Go's stack
+	// per call than the call depth accounts for
+	open := strings.Repeat("agar 1 { jabtak 1 {\n", 20)
+	end := strings.Repeat("} }\n", 20)
+	nested := "firseKaro f(n) {\n" + open + "wapas bhejo f(n + 1)\n" + end + "}\nf(0)"
+
+	for name, src := range map[string]string{
+		"recursion":               `firseKaro f(n) { wapas bhejo f(n + 1) }` + "\nf(0)",
+		"recursion inside blocks": nested,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := run(t, src)
+			if !errors.Is(err, ErrStackOverflow) {
+				t.Fatalf("error = %v, want ErrStackOverflow", err)
+			}
+			if IsLimitError(err) {
+				t.Errorf("IsLimitError(%v) = true, want false", err)
+			}
+		})
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"doubling string", `ye s = "ab"
+dohraye { s = s + s }`},
+		{"growing template", `ye s = "ab"
+dohraye { s = "{s}{s}" }`},
+		{"rest parameters", `firseKaro f(...baaki) { wapas bhejo lambai(baaki) }
+ye n = 0
+dohraye { n = n + f(1, 2, 3, 4, 5, 6, 7, 8, 9, 10) }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, tt.src, WithMemoryLimit(1<<20), WithStepLimit(1000000))
+			if !errors.Is(err, ErrMemoryLimit) {
+				t.Fatalf("error = %v, want ErrMemoryLimit", err)
+			}
+			if !IsLimitError(err) {
+				t.Errorf("IsLimitError(%v) = false", err)
+			}
+			if !strings.Contains(err.Error(), "memory limit of 1048576 bytes exceeded") {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+
+	if _, err := run(t, `ye s = "ab"
+dohraye { s = s + s }`, WithMemoryLimit(0), WithStepLimit(20)); errors.Is(err, ErrMemoryLimit) {
+		t.Errorf("no memory limit, error = %v", err)
+	}
+}
+
+func TestStepLimit(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		n    float64 // what n is once the limit of 100 steps stops the program
+	}{
+		{"dohraye", `ye n = 0
+dohraye { n = n + 1 }`, 100},
+		{"jabtak", `ye n = 0
+jabtak 1 { n = n + 1 }`, 100},
+		{"function calls", `ye n = 0
+firseKaro f() { n = n + 1 }
+dohraye { f() }`, 50},
+		{"builtin calls", `ye n = 0
+dohraye { n = n + lambai("a") }`, 50},
+		{"recursion", `ye n = 0
+firseKaro f() {
+	n = n + 1
+	f()
+}
+f()`, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := parser.Parse(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			interpreter := NewInterpreter(WithStepLimit(100))
+			_, err = interpreter.Run(program)
+			if !errors.Is(err, ErrStepLimit) || !IsLimitError(err) {
+				t.Fatalf("error = %v, want ErrStepLimit", err)
+			}
+			if n, _ := interpreter.Globals().Get("n"); n.(*NumberValue).Value != tt.n {
+				t.Errorf("stopped with n = %v, want %v", n.(*NumberValue).Value, tt.n)
+			}
+		})
+	}
+
+	_, err := run(t, "dohraye {}", WithStepLimit(1000))
+	if err == nil || err.Error() != "line 1, column 1: step limit of 1000 exceeded" {
+		t.Errorf("error = %v", err)
+	}
+	// 100 checks of the condition and a call of bol
+	exact := `ye n = 0
+jabtak n < 99 { n = n + 1 }
+bol(n)`
+	if out, err := run(t, exact, WithStepLimit(101)); err != nil || out != "99\n" {
+		t.Errorf("a program of 101 steps printed %q, %v with a limit of 101", out, err)
+	}
+	if _, err := run(t, exact, WithStepLimit(100)); !errors.Is(err, ErrStepLimit) {
+		t.Errorf("a program of 101 steps stopped with %v with a limit of 100", err)
+	}
+}
+
+func TestContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	programs := map[string]string{
+		"dohraye": `dohraye {}`,
+		"jabtak":  `jabtak 1 {}`,
+		// calls itself twice per call, so it ends long after the deadline
+		"recursion": `firseKaro f(n) {
+	agar n > 0 {
+		f(n - 1)
+		f(n - 1)
+	}
+}
+f(100)`,
+	}
+	contexts := []struct {
+		name string
+		ctx  context.Context
+		err  error
+	}{
+		{"cancelled", cancelled, context.Canceled},
+		{"expired", expired, context.DeadlineExceeded},
+	}
+	for _, c := range contexts {
+		for name, src := range programs {
+			t.Run(c.name+" "+name, func(t *testing.T) {
+				_, err := run(t, src, WithContext(c.ctx))
+				if !errors.Is(err, c.err) {
+					t.Fatalf("error = %v, want %v", err, c.err)
+				}
+				if !strings.Contains(err.Error(), "execution stopped: "+c.err.Error()) {
+					t.Errorf("error = %v", err)
+				}
+				if IsLimitError(err) != (c.err == context.DeadlineExceeded) {
+					t.Errorf("IsLimitError(%v) = %v", err, IsLimitError(err))
+				}
+			})
+		}
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "values", src: `ye naam = "Baburao"
+bol("Namaste {naam}, umar {20 + 1}")`, out: "Namaste Baburao, umar 21\n"},
+		{name: "formatting", src: `firseKaro f() {}
+bol("{10 / 4} {3} {1 < 2} {tarks} {f()}")`, out: "2.5 3 true [] null\n"},
+		{name: "escaped brace", src: `ye a = 1
+bol("\{a} {a}")`, out: "{a} 1\n"},
+		{name: "string in a placeholder", src: `bol("{'a' + 1} {lambai('a}b')}")`, out: "a1 3\n"},
+		{name: "nested template", src: `ye a = 1
+bol("{'<{a + 1}>'}")`, out: "<2>\n"},
+		{name: "evaluated in order", src: `firseKaro f(x) {
+	bol(x)
+	wapas bhejo x
+}
+bol("{f(1)}{f(2)}")`, out: "1\n2\n12\n"},
+		{name: "evaluated each time", src: `ye n = 0
+dohraye {
+	n = n + 1
+	bol("n={n}")
+	agar n == 2 { roko }
+}`, out: "n=1\nn=2\n"},
+		{name: "error in a placeholder", src: `bol("a {b}")`, err: "line 1, column 9: undefined variable: b"},
+		{name: "error on a later line", src: `bol("a
+  {1 / 0}")`, err: "line 2, column 6: division by zero"},
+	})
+}
+
+func TestTraceback(t *testing.T) {
+	nested := `firseKaro bahar() {
+	wapas bhejo beech(1)
+}
+firseKaro beech(a) {
+	ye b = a + 1
+	wapas bhejo andar(b)
+}
+firseKaro andar(c) { wapas bhejo c / 0 }
+
+bol(bahar())`
+	recursion := `firseKaro f(n) {
+	agar n == 0 {
+		wapas bhejo nahi
+	}
+	wapas bhejo f(n - 1)
+}
+f(10)`
+	short := strings.Replace(recursion, "f(10)", "f(2)", 1)
+	tests := []struct {
+		name   string
+		src    string
+		source string // the source given to Traceback
+		want   string
+	}{
+		{"nested calls", nested, nested, `Traceback (most recent call last):
+  line 10, in <main>
+    bol(bahar())
+  line 2, in bahar
+    wapas bhejo beech(1)
+  line 6, in beech
+    wapas bhejo andar(b)
+  line 8, in andar
+    firseKaro andar(c) { wapas bhejo c / 0 }
+`},
+		{"without source", nested, "", `Traceback (most recent call last):
+  line 10, in <main>
+  line 2, in bahar
+  line 6, in beech
+  line 8, in andar
+`},
+		// the recursive call is printed three times, then folded
+		{"deep recursion", recursion, recursion, `Traceback (most recent call last):
+  line 7, in <main>
+    f(10)
+  line 5, in f
+    wapas bhejo f(n - 1)
+  line 5, in f
+    wapas bhejo f(n - 1)
+  line 5, in f
+    wapas bhejo f(n - 1)
+  [previous line repeated 7 more times]
+  line 3, in f
+    wapas bhejo nahi
+`},
+		{"short recursion", short, short, `Traceback (most recent call last):
+  line 7, in <main>
+    f(2)
+  line 5, in f
+    wapas bhejo f(n - 1)
+  line 5, in f
+    wapas bhejo f(n - 1)
+  line 3, in f
+    wapas bhejo nahi
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, tt.src)
+			var rtErr *RuntimeError
+			if !errors.As(err, &rtErr) {
+				t.Fatalf("error %v is not a *RuntimeError", err)
+			}
+			if got := rtErr.Traceback(tt.source); got != tt.want {
+				t.Errorf("traceback:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunStreams(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		stdin  string
+		stdout string
+		stderr string
+	}{
+		{name: "output", src: `bol("a", 1)`, stdout: "a\n1\n"},
+		{name: "input", src: `bol(pucho("naam? "))
+bol(pucho())`, stdin: "Baburao\nGanpat\n", stdout: "naam? Baburao\nGanpat\n"},
+		{name: "syntax errors", src: "ye = 1\nbol(", stderr: "Syntax Error: line 1, column 4: expected a name after ye but found \"=\"\n" +
+			"Syntax Error: line 2, column 4: expected ) to close the call to bol but found end of input\n"},
+		{name: "runtime error", src: `bol("pehle")
+bol(1 / 0)`, stdout: "pehle\n", stderr: `Traceback (most recent call last):
+  line 2, in <main>
+    bol(1 / 0)
+Runtime Error: division by zero
+`},
+		{name: "limit error", src: `dohraye {}`, stderr: `Traceback (most recent call last):
+  line 1, in <main>
+    dohraye {}
+Limit Error: step limit of 10 exceeded
+`},
+		{name: "bahar", src: `bol(1)
+bahar(3)
+bol(2)`, stdout: "1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			Run(tt.src, WithStdout(&stdout), WithStderr(&stderr), WithStdin(strings.NewReader(tt.stdin)), WithStepLimit(10))
+			if stdout.String() != tt.stdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.stdout)
+			}
+			if stderr.String() != tt.stderr {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
+
+func TestShebang(t *testing.T) {
+	testRuns(t, []runTest{
+		{name: "skipped", src: "#!/usr/bin/env hlang\nbol(\"chal gaya\")\n", out: "chal gaya\n"},
+		{name: "error lines", src: "#!/usr/bin/env hlang\nbol(1)\nbol(1 / 0)", out: "1\n", err: "line 3, column 7: division by zero"},
+	})
+
+	_, err := Compile("bol(1)\n#!/usr/bin/env hlang")
+	if err == nil {
+		t.Error("a shebang after the first line compiled")
+	}
+}
@@ -0,0 +1,90 @@
+package interp
+
+import "fmt"
+
+// NativeFunction is a function written in Go that scripts can call like
+// any other function
+type NativeFunction struct {
+	Name  string
+	Arity int // -1 accepts any number of arguments
+	Fn    func(args []RuntimeValue) (RuntimeValue, error)
+}
+
+func (n *NativeFunction) Type() string { return "function" }
+func (n *NativeFunction) GoValue() any { return n }
+
+// RegisterFunc makes a Go function callable from scripts under name.
+// Calls with a different number of arguments than arity fail before fn
+// runs, pass -1 to accept any number. Errors returned by fn are reported
+// as runtime errors of the call, prefixed with the function's name.
+func (i *Interpreter) RegisterFunc(name string, arity int, fn func(args []RuntimeValue) (RuntimeValue, error)) {
+	i.globals().Define(name, &NativeFunction{Name: name, Arity: arity, Fn: fn})
+}
+
+// WithFunc registers a Go function when the interpreter is created, the
+// way to give a Script's runs access to host functions
+func WithFunc(name string, arity int, fn func(args []RuntimeValue) (RuntimeValue, error)) Option {
+	return func(i *Interpreter) {
+		i.RegisterFunc(name, arity, fn)
+	}
+}
+
+// globals is the outermost environment, where builtins live
+func (i *Interpreter) globals() *Environment {
+	env := i.env
+	for env.parent != nil {
+		env = env.parent
+	}
+	return env
+}
+
+func (i *Interpreter) callNative(fn *NativeFunction, args []RuntimeValue) (RuntimeValue, error) {
+	if fn.Arity >= 0 && len(args) != fn.Arity {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", fn.Name, fn.Arity, len(args))
+	}
+
+	if err := i.step(); err != nil {
+		return nil, err
+	}
+
+	result, err := fn.Fn(args)
+	if err != nil {
+		if _, ok := err.(*RuntimeError); ok {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %w", fn.Name, err)
+	}
+	if result == nil {
+		result = &NullValue{}
+	}
+	return result, nil
+}
+
+// NumberArg returns args[idx] as a number, or the same error builtins
+// give for a wrong argument type
+func NumberArg(args []RuntimeValue, idx int) (float64, error) {
+	if num, ok := args[idx].(*NumberValue); ok {
+		return num.Value, nil
+	}
+	return 0, argTypeError(args, idx, "number")
+}
+
+// StringArg returns args[idx] as a string
+func StringArg(args []RuntimeValue, idx int) (string, error) {
+	if str, ok := args[idx].(*StringValue); ok {
+		return str.Value, nil
+	}
+	return "", argTypeError(args, idx, "string")
+}
+
+// BoolArg returns args[idx] as a bool
+func BoolArg(args []RuntimeValue, idx int) (bool, error) {
+	if b, ok := args[idx].(*BoolValue); ok {
+		return b.Value, nil
+	}
+	return false, argTypeError(args, idx, "bool")
+}
+
+func argTypeError(args []RuntimeValue, idx int, want string) error {
+	return fmt.Errorf("argument %d must be a %s, got %s", idx+1, want, args[idx].Type())
+}
@@ -0,0 +1,88 @@
+package interp
+
+import (
+	"errors"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"strings"
+	"testing"
+)
+
+func TestRegisterFunc(t *testing.T) {
+	funcs := []Option{
+		WithFunc("jodo", 2, func(args []RuntimeValue) (RuntimeValue, error) {
+			a, err := NumberArg(args, 0)
+			if err != nil {
+				return nil, err
+			}
+			b, err := NumberArg(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return &NumberValue{Value: a + b}, nil
+		}),
+		WithFunc("dohrao", 2, func(args []RuntimeValue) (RuntimeValue, error) {
+			s, err := StringArg(args, 0)
+			if err != nil {
+				return nil, err
+			}
+			n, err := NumberArg(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return &StringValue{Value: strings.Repeat(s, int(n))}, nil
+		}),
+		WithFunc("ulta", 1, func(args []RuntimeValue) (RuntimeValue, error) {
+			b, err := BoolArg(args, 0)
+			if err != nil {
+				return nil, err
+			}
+			return &BoolValue{Value: !b}, nil
+		}),
+		WithFunc("ginti", -1, func(args []RuntimeValue) (RuntimeValue, error) {
+			return &NumberValue{Value: float64(len(args))}, nil
+		}),
+		WithFunc("kuch_nahi", 0, func(args []RuntimeValue) (RuntimeValue, error) {
+			return nil, nil
+		}),
+		WithFunc("fail", 0, func(args []RuntimeValue) (RuntimeValue, error) {
+			return nil, errors.New("something broke")
+		}),
+	}
+	tests := []runTest{
+		{name: "call", src: `bol(jodo(1, 2), dohrao("ab", 2), ulta(1 > 2))`, out: "3\nabab\ntrue\n"},
+		{name: "any number of arguments", src: `bol(ginti(), ginti(1, "a", 3))`, out: "0\n3\n"},
+		{name: "nil result", src: `bol(kuch_nahi())`, out: "null\n"},
+		{name: "as a value", src: `ye f = jodo
+bol(f(2, 3))`, out: "5\n"},
+		{name: "too few arguments", src: `jodo(1)`, err: "line 1, column 1: jodo expects 2 argument(s), got 1"},
+		{name: "too many arguments", src: `kuch_nahi(1)`, err: "kuch_nahi expects 0 argument(s), got 1"},
+		{name: "number argument", src: `bol(jodo(1, "2"))`, err: "line 1, column 5: jodo: argument 2 must be a number, got string"},
+		{name: "string argument", src: `dohrao(1, 2)`, err: "dohrao: argument 1 must be a string, got number"},
+		{name: "bool argument", src: `ulta(tarks)`, err: "ulta: argument 1 must be a bool, got list"},
+		{name: "error", src: `fail()`, err: "line 1, column 1: fail: something broke"},
+		// errors read the same as those of the builtins
+		{name: "builtin", src: `nikalo(tarks, "0")`, err: "nikalo: argument 2 must be a number, got string"},
+	}
+	testRuns(t, tests, funcs...)
+}
+
+func TestRegisterFuncReplaces(t *testing.T) {
+	var out strings.Builder
+	interpreter := NewInterpreter(WithStdout(&out))
+	interpreter.RegisterFunc("bol", 1, func(args []RuntimeValue) (RuntimeValue, error) {
+		out.WriteString("mera bol\n")
+		return nil, nil
+	})
+	program, err := parser.Parse(`bol(1)
+bol(1, 2)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = interpreter.Run(program)
+	if err == nil || err.Error() != "line 2, column 1: bol expects 1 argument(s), got 2" {
+		t.Errorf("error = %v", err)
+	}
+	if out.String() != "mera bol\n" {
+		t.Errorf("printed %q", out.String())
+	}
+}
@@ -0,0 +1,159 @@
+package interp
+
+import (
+	"context"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/parser"
+	"github.com/suraj-9849/hindiLang.git/token"
+)
+
+// Script is a parsed program that can be run any number of times, also
+// from several goroutines at once. Every run gets its own interpreter and
+// environment, the parsed program itself is never modified.
+type Script struct {
+	program *ast.Program
+}
+
+// Compile lexes and parses src. The error is lexer.SyntaxErrors holding
+// every syntax error found.
+func Compile(src string) (*Script, error) {
+	program, err := parser.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Script{program: program}, nil
+}
+
+// Run runs the script with globals defined as variables, converted with
+// ToValue. It returns the value of a top level "wapas bhejo", or else of
+// the last statement, converted with FromValue. Errors while running are
+// *RuntimeError. ctx stops the run when it is cancelled.
+func (s *Script) Run(ctx context.Context, globals map[string]any, opts ...Option) (any, error) {
+	interpreter, err := start(ctx, globals, opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := interpreter.Run(s.program)
+	if err != nil {
+		return nil, err
+	}
+	return FromValue(result), nil
+}
+
+// Load runs the script like Run does and returns the interpreter holding
+// its variables and functions, so the host can call them afterwards with
+// Interpreter.Call. An Interpreter must not be used from several
+// goroutines at once, Load once per goroutine instead.
+func (s *Script) Load(ctx context.Context, globals map[string]any, opts ...Option) (*Interpreter, error) {
+	interpreter, err := start(ctx, globals, opts)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := interpreter.Run(s.program); err != nil {
+		return nil, err
+	}
+	return interpreter, nil
+}
+
+// start creates the interpreter of one run of a script, with globals
+// defined as variables
+func start(ctx context.Context, globals map[string]any, opts []Option) (*Interpreter, error) {
+	interpreter := NewInterpreter(append([]Option{WithContext(ctx)}, opts...)...)
+	for name, value := range globals {
+		val, err := ToValue(value)
+		if err != nil {
+			return nil, err
+		}
+		interpreter.env.Define(name, val)
+	}
+	return interpreter, nil
+}
+
+// Run evaluates a whole program, giving the value of a top level
+// "wapas bhejo" if there is one. Programs run one after another on the
+// same interpreter share their variables, which is how the REPL works.
+func (i *Interpreter) Run(program *ast.Program) (RuntimeValue, error) {
+	result, err := i.Evaluate(program)
+	if err != nil {
+		i.controlFlow = nil
+		return nil, err
+	}
+	if i.controlFlow != nil && i.controlFlow.Type == "return" {
+		result = i.controlFlow.Value
+	}
+	i.controlFlow = nil
+	return result, nil
+}
+
+// RunContext runs a program like Run, stopping it once ctx is cancelled
+// instead of the context the interpreter was created with. The REPL runs
+// each input this way, so Ctrl+C stops only that input.
+func (i *Interpreter) RunContext(ctx context.Context, program *ast.Program) (RuntimeValue, error) {
+	saved := i.ctx
+	i.ctx = ctx
+	defer func() { i.ctx = saved }()
+	return i.Run(program)
+}
+
+// Globals is the environment holding the program's top level variables
+// and functions
+func (i *Interpreter) Globals() *Environment {
+	return i.globals()
+}
+
+// Format turns a value into the text bol would print for it
+func (i *Interpreter) Format(val RuntimeValue) string {
+	return i.toString(val)
+}
+
+// Truthy reports whether agar and jabtak take val as true
+func (i *Interpreter) Truthy(val RuntimeValue) bool {
+	return i.isTruthy(val)
+}
+
+// Call calls the function a script defined under name with Go arguments,
+// converted with ToValue, and returns its result converted with
+// FromValue
+func (i *Interpreter) Call(name string, args ...any) (any, error) {
+	fn, err := i.Globals().Get(name)
+	if err != nil {
+		return nil, err
+	}
+	switch fn.(type) {
+	case *FunctionValue, *NativeFunction:
+	default:
+		return nil, fmt.Errorf("%s is not a function", name)
+	}
+
+	values := make([]RuntimeValue, len(args))
+	for idx, arg := range args {
+		val, err := ToValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", idx+1, err)
+		}
+		values[idx] = val
+	}
+
+	result, err := i.CallValue(fn, values...)
+	if err != nil {
+		return nil, err
+	}
+	return FromValue(result), nil
+}
+
+// CallValue calls a function value, either a *FunctionValue from the
+// script or a *NativeFunction. Errors are *RuntimeError with the stack of
+// the call.
+func (i *Interpreter) CallValue(fn RuntimeValue, args ...RuntimeValue) (RuntimeValue, error) {
+	result, err := i.callValue(fn, args, token.Position{})
+	if err != nil {
+		if _, ok := err.(*RuntimeError); !ok {
+			rtErr := i.runtimeError(token.Position{}, err.Error())
+			rtErr.Err = err
+			err = rtErr
+		}
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,139 @@
+package interp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	script, err := Compile(`firseKaro jodo(a, b) { wapas bhejo a + b }
+firseKaro fail() { wapas bhejo 1 + nahi }
+ye sankhya = 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interpreter, err := script.Load(context.Background(), nil,
+		WithStdout(io.Discard),
+		WithFunc("daam", 2, func(args []RuntimeValue) (RuntimeValue, error) {
+			return &NumberValue{Value: 1}, nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := interpreter.Call("jodo", 1, 2); err != nil || got != 3.0 {
+		t.Errorf("jodo(1, 2) = %v, %v, want 3", got, err)
+	}
+
+	tests := []struct {
+		name string
+		args []any
+		err  string
+	}{
+		{"daam", []any{1}, "daam expects 2 argument(s), got 1"},
+		{"jodo", []any{1}, "jodo expects 2 argument(s), got 1"},
+		{"nahi", nil, "undefined variable: nahi"},
+		{"sankhya", nil, "sankhya is not a function"},
+		{"fail", nil, "line 2, column 36: undefined variable: nahi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interpreter.Call(tt.name, tt.args...)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+
+	_, err = interpreter.Call("daam", 1)
+	var rtErr *RuntimeError
+	if !errors.As(err, &rtErr) {
+		t.Fatalf("error %v is not a *RuntimeError", err)
+	}
+	if tb := rtErr.Traceback(""); tb != "Traceback (most recent call last):\n  in <main>\n" {
+		t.Errorf("traceback of a call from Go:\n%s", tb)
+	}
+}
+
+// TestLoadCall is the example of calling a script's function in README.md
+func TestLoadCall(t *testing.T) {
+	script, err := Compile(`
+firseKaro chhoot(kul) {
+	agar kul > 100 {
+		wapas bhejo kul * 0.9
+	}
+	wapas bhejo kul
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := script.Load(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct{ kul, want float64 }{{120, 108}, {80, 80}} {
+		if price, err := rules.Call("chhoot", tt.kul); err != nil || price != tt.want {
+			t.Errorf("chhoot(%v) = %v, %v, want %v", tt.kul, price, err, tt.want)
+		}
+	}
+}
+
+func TestScriptConcurrent(t *testing.T) {
+	script, err := Compile(`
+ye kul = 0
+firseKaro jodo(...sankhyaen) {
+	ye i = 0
+	jabtak i < lambai(sankhyaen) {
+		kul = kul + nikalo(sankhyaen, i)
+		i = i + 1
+	}
+}
+ye n = 0
+dohraye {
+	jodo(n, shuru)
+	n = n + 1
+	agar n == 100 { roko }
+}
+bol("{naam} {kul}")
+kul
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const runs = 16
+	var wg sync.WaitGroup
+	errs := make([]error, runs)
+	for idx := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var out bytes.Buffer
+			name := fmt.Sprintf("run%d", idx)
+			got, err := script.Run(context.Background(), map[string]any{"naam": name, "shuru": idx},
+				WithStdout(&out), WithStepLimit(10000))
+			// 0 + 1 + ... + 99 and 100 times idx
+			want := float64(4950 + 100*idx)
+			switch {
+			case err != nil:
+				errs[idx] = err
+			case got != want:
+				errs[idx] = fmt.Errorf("returned %v, want %v", got, want)
+			case out.String() != fmt.Sprintf("%s %v\n", name, want):
+				errs[idx] = fmt.Errorf("printed %q", out.String())
+			}
+		}()
+	}
+	wg.Wait()
+	for idx, err := range errs {
+		if err != nil {
+			t.Errorf("run %d: %v", idx, err)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package lexer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/token"
+)
+
+// SyntaxError is reported by the lexer and the parser with the position
+// it happened at
+type SyntaxError struct {
+	Pos     token.Position
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// SyntaxErrors holds every syntax error found in a program, each one a
+// *SyntaxError. parser.Parse, interp.Compile and interp.Run return it for
+// code that does not parse.
+type SyntaxErrors []error
+
+func (e SyntaxErrors) Error() string {
+	return errors.Join(e...).Error()
+}
+
+func (e SyntaxErrors) Unwrap() []error {
+	return e
+}
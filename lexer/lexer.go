@@ -0,0 +1,524 @@
+// Package lexer turns HindiScript source into tokens.
+//
+// Like Go, line ends end statements: the lexer adds a SEMICOLON token with
+// the Value "\n" at the end of a line whose last token can end a
+// statement. Comments are not tokens, they are kept aside in Comments,
+// except doc comments (/// and /** */) starting a line, which are also
+// DOC_COMMENT tokens so the parser can attach them to the declaration
+// after them.
+package lexer
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/token"
+	"strings"
+)
+
+// Lexer holds the state of scanning one source, create it with New
+type Lexer struct {
+	input    []rune
+	position int
+	length   int
+	tokens   []token.Token
+	errors   []error
+	comments []token.Comment
+
+	// line ends don't end statements inside parentheses
+	parenDepth int
+
+	// atLineStart is set until the current line has a token, only a doc
+	// comment starting a line documents anything
+	atLineStart bool
+
+	// start is where the token being scanned begins; line, lineStart and
+	// scanned let positionAt turn offsets into line/column lazily
+	start     int
+	line      int
+	lineStart int
+	scanned   int
+}
+
+// New creates a lexer for a whole program
+func New(input string) *Lexer {
+	return newAt(input, token.Position{Line: 1, Column: 1})
+}
+
+// NewExpression creates a lexer for a single expression whose first rune
+// sits at pos, like the placeholders inside template strings. Line ends
+// in it never end a statement, as inside parentheses.
+func NewExpression(input string, pos token.Position) *Lexer {
+	l := newAt(input, pos)
+	l.parenDepth = 1
+	return l
+}
+
+func newAt(input string, pos token.Position) *Lexer {
+	runes := []rune(input) //type rune = int32
+	return &Lexer{
+		input:       runes,
+		position:    0,
+		length:      len(runes),
+		tokens:      make([]token.Token, 0),
+		atLineStart: true,
+		line:        pos.Line,
+		lineStart:   1 - pos.Column,
+	}
+}
+
+// Tokenize scans the whole input and returns its tokens. Problems are
+// skipped over and reported by Errors.
+func (l *Lexer) Tokenize() []token.Token {
+	l.skipShebang()
+	for l.position < l.length {
+		l.start = l.position
+		if l.skipWhitespace() {
+			continue
+		}
+		if l.skipComment() {
+			continue
+		}
+		if l.scanString() {
+			continue
+		}
+		if l.scanNumber() {
+			continue
+		}
+		if l.scanIdentifierOrKeyword() {
+			continue
+		}
+		if l.scanMultiCharOperator() {
+			continue
+		}
+		if l.scanSingleCharToken() {
+			continue
+		}
+		l.position++
+	}
+	return l.tokens
+}
+
+// skipShebang skips a "#!/usr/bin/env hlang" first line, so .hlang files
+// can be run as executables
+func (l *Lexer) skipShebang() {
+	if l.position != 0 || l.current() != '#' || l.peek(1) != '!' {
+		return
+	}
+	for l.position < l.length && l.current() != '\n' {
+		l.advance()
+	}
+}
+
+func (l *Lexer) current() rune {
+	if l.position >= l.length {
+		return 0
+	}
+	return l.input[l.position]
+}
+
+func (l *Lexer) peek(offset int) rune {
+	pos := l.position + offset
+	if pos >= l.length {
+		return 0
+	}
+	return l.input[pos]
+}
+
+func (l *Lexer) advance() {
+	l.position++
+}
+
+func (l *Lexer) addToken(tokenType, value string) {
+	l.tokens = append(l.tokens, token.Token{Type: tokenType, Value: value, Pos: l.positionAt(l.start)})
+	l.atLineStart = false
+}
+
+// positionAt only moves forward, tokens are added in source order
+func (l *Lexer) positionAt(offset int) token.Position {
+	for l.scanned < offset && l.scanned < l.length {
+		if l.input[l.scanned] == '\n' {
+			l.line++
+			l.lineStart = l.scanned + 1
+		}
+		l.scanned++
+	}
+	return token.Position{Line: l.line, Column: offset - l.lineStart + 1}
+}
+
+func (l *Lexer) skipWhitespace() bool {
+	r := l.current()
+	if r == '\n' {
+		l.terminateLine()
+		l.atLineStart = true
+		l.advance()
+		return true
+	}
+	if r == ' ' || r == '\t' || r == '\r' {
+		l.advance()
+		return true
+	}
+	return false
+}
+
+// terminateLine adds an automatic SEMICOLON (with the value "\n") at a
+// line end when the line's last token can end a statement, like Go does.
+// A line ending in an operator, a comma or an open bracket continues on
+// the next line.
+func (l *Lexer) terminateLine() {
+	if l.parenDepth > 0 || len(l.tokens) == 0 {
+		return
+	}
+
+	last := l.tokens[len(l.tokens)-1]
+	switch last.Type {
+	case token.Identifier, token.Number, token.String, token.Template:
+		l.addToken(token.Semicolon, "\n")
+	case token.Paren, token.Brace:
+		if last.Value == ")" || last.Value == "}" {
+			l.addToken(token.Semicolon, "\n")
+		}
+	case token.Keyword:
+		switch last.Value {
+		case "roko", "aage badho", "wapas bhejo":
+			l.addToken(token.Semicolon, "\n")
+		}
+	}
+}
+
+// skipComment keeps // and /* */ comments out of the token stream, they
+// are only recorded in Comments. Doc comments (/// and /** */) starting a
+// line are also kept as DOC_COMMENT tokens so the parser can attach them
+// to the declaration that follows, after code they are plain comments.
+func (l *Lexer) skipComment() bool {
+	if l.current() != '/' {
+		return false
+	}
+
+	if l.peek(1) == '/' {
+		isDoc := l.atLineStart && l.peek(2) == '/' && l.peek(3) != '/'
+		l.position += 2
+		start := l.position
+		for l.position < l.length && l.current() != '\n' {
+			l.advance()
+		}
+		l.addComment()
+		if isDoc {
+			l.addToken(token.DocComment, cleanLineDoc(string(l.input[start+1:l.position])))
+		}
+		return true
+	}
+
+	if l.peek(1) == '*' {
+		isDoc := l.atLineStart && l.peek(2) == '*' && l.peek(3) != '*' && l.peek(3) != '/'
+		l.position += 2
+		start := l.position
+		hasNewline := false
+		depth := 1
+		for l.position < l.length && depth > 0 {
+			if l.current() == '/' && l.peek(1) == '*' {
+				depth++
+				l.position += 2
+			} else if l.current() == '*' && l.peek(1) == '/' {
+				depth--
+				l.position += 2
+			} else {
+				if l.current() == '\n' {
+					hasNewline = true
+				}
+				l.advance()
+			}
+		}
+		if depth > 0 {
+			l.errorf(l.positionAt(l.start), "unterminated block comment")
+			return true
+		}
+		l.addComment()
+		if isDoc {
+			l.addToken(token.DocComment, cleanBlockDoc(string(l.input[start+1:l.position-2])))
+		} else if hasNewline {
+			l.terminateLine()
+			l.atLineStart = true
+		}
+		return true
+	}
+
+	return false
+}
+
+func (l *Lexer) addComment() {
+	text := strings.TrimRight(string(l.input[l.start:l.position]), "\r")
+	l.comments = append(l.comments, token.Comment{Text: text, Pos: l.positionAt(l.start)})
+}
+
+// Comments returns the comments skipped while scanning, in source order
+func (l *Lexer) Comments() []token.Comment {
+	return l.comments
+}
+
+// Errors returns the problems found while scanning, like an unterminated
+// block comment
+func (l *Lexer) Errors() []error {
+	return l.errors
+}
+
+func (l *Lexer) errorf(pos token.Position, format string, args ...interface{}) {
+	l.errors = append(l.errors, &SyntaxError{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+func cleanLineDoc(text string) string {
+	return strings.TrimRight(strings.TrimPrefix(text, " "), " \t\r")
+}
+
+// cleanBlockDoc removes the leading " * " that doc blocks usually have on
+// every line
+func cleanBlockDoc(text string) string {
+	lines := strings.Split(text, "\n")
+	for idx, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "*") {
+			line = strings.TrimPrefix(strings.TrimPrefix(line, "*"), " ")
+		}
+		lines[idx] = line
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// scanString emits a STRING token, or a TEMPLATE token holding the raw
+// text between the quotes when it contains a {placeholder}. The parser
+// splits templates itself so placeholder positions stay exact. Both keep
+// the string as written, quotes included, under "Raw".
+func (l *Lexer) scanString() bool {
+	quote := l.current()
+	if quote != '"' && quote != '\'' {
+		return false
+	}
+
+	l.advance()
+	contentStart := l.position
+	value := []rune{}
+	isTemplate := false
+
+	for l.position < l.length {
+		r := l.current()
+
+		if r == '\\' && l.position+1 < l.length {
+			value = append(value, l.peek(1))
+			l.position += 2
+			continue
+		}
+
+		if r == '{' {
+			isTemplate = true
+			l.skipPlaceholder(quote)
+			continue
+		}
+
+		if r == quote {
+			raw := string(l.input[contentStart:l.position])
+			l.advance()
+			l.addStringToken(isTemplate, raw, value)
+			return true
+		}
+
+		value = append(value, r)
+		l.advance()
+	}
+	l.addStringToken(isTemplate, string(l.input[contentStart:l.position]), value)
+	return true
+}
+
+func (l *Lexer) addStringToken(isTemplate bool, raw string, value []rune) {
+	if isTemplate {
+		l.addToken(token.Template, raw)
+	} else {
+		l.addToken(token.String, string(value))
+	}
+	l.tokens[len(l.tokens)-1].Raw = string(l.input[l.start:l.position])
+}
+
+// skipPlaceholder moves past a {...} inside a string quoted with quote,
+// allowing nested braces and strings in the other quote so "{f('x')}"
+// works. The string's own quote always ends it, so a placeholder missing
+// its } doesn't swallow the rest of the source.
+func (l *Lexer) skipPlaceholder(quote rune) {
+	depth := 0
+	inner := rune(0) // the quote of a string inside the placeholder
+	for l.position < l.length {
+		r := l.current()
+		switch {
+		case inner != 0 && r == '\\':
+			l.advance()
+		case r == quote:
+			return
+		case inner != 0:
+			if r == inner {
+				inner = 0
+			}
+		case r == '"' || r == '\'':
+			inner = r
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+			if depth == 0 {
+				l.advance()
+				return
+			}
+		}
+		l.advance()
+	}
+}
+
+func (l *Lexer) scanNumber() bool {
+	if !isDigit(l.current()) {
+		return false
+	}
+
+	start := l.position
+	for l.position < l.length && (isDigit(l.current()) || l.current() == '.') {
+		l.advance()
+	}
+
+	l.addToken(token.Number, string(l.input[start:l.position]))
+	return true
+}
+
+func (l *Lexer) scanIdentifierOrKeyword() bool {
+	if !isLetter(l.current()) {
+		return false
+	}
+
+	start := l.position
+	l.advance()
+
+	for l.position < l.length && (isLetter(l.current()) || isDigit(l.current())) {
+		l.advance()
+	}
+
+	word := string(l.input[start:l.position])
+
+	switch word {
+	case "ya":
+		word = l.tryConsumeMultiWordKeyword(word, "fir")
+	case "aage":
+		word = l.tryConsumeMultiWordKeyword(word, "badho")
+	case "wapas":
+		word = l.tryConsumeMultiWordKeyword(word, "bhejo")
+	}
+
+	if token.IsKeyword(word) {
+		l.addToken(token.Keyword, word)
+	} else {
+		l.addToken(token.Identifier, word)
+	}
+
+	return true
+}
+
+func (l *Lexer) tryConsumeMultiWordKeyword(firstWord, secondWord string) string {
+	savedPos := l.position
+
+	// both words must be on the same line
+	for l.position < l.length && (l.current() == ' ' || l.current() == '\t') {
+		l.advance()
+	}
+
+	if l.position < l.length && isLetter(l.current()) {
+		start := l.position
+		l.advance()
+
+		for l.position < l.length && (isLetter(l.current()) || isDigit(l.current())) {
+			l.advance()
+		}
+
+		nextWord := string(l.input[start:l.position])
+		if nextWord == secondWord {
+			return firstWord + " " + secondWord
+		}
+
+		l.position = savedPos
+	} else {
+		l.position = savedPos
+	}
+
+	return firstWord
+}
+
+func (l *Lexer) scanMultiCharOperator() bool {
+	if l.position+1 >= l.length {
+		return false
+	}
+
+	// "..." marks a rest parameter
+	if l.current() == '.' && l.peek(1) == '.' && l.peek(2) == '.' {
+		l.addToken(token.Operator, "...")
+		l.position += 3
+		return true
+	}
+
+	twoChar := string([]rune{l.current(), l.peek(1)})
+
+	switch twoChar {
+	case "==", "!=", "<=", ">=", "&&", "||":
+		l.addToken(token.Operator, twoChar)
+		l.position += 2
+		return true
+	}
+
+	return false
+}
+
+func (l *Lexer) scanSingleCharToken() bool {
+	r := l.current()
+
+	switch r {
+	case '=', '+', '-', '*', '/', '%', '<', '>':
+		l.addToken(token.Operator, string(r))
+		l.advance()
+		return true
+	case '(':
+		l.addToken(token.Paren, "(")
+		l.parenDepth++
+		l.advance()
+		return true
+	case ')':
+		l.addToken(token.Paren, ")")
+		if l.parenDepth > 0 {
+			l.parenDepth--
+		}
+		l.advance()
+		return true
+	case '{':
+		l.addToken(token.Brace, "{")
+		l.advance()
+		return true
+	case '}':
+		l.addToken(token.Brace, "}")
+		l.advance()
+		return true
+	case ',':
+		l.addToken(token.Comma, ",")
+		l.advance()
+		return true
+	case ':':
+		l.addToken(token.Colon, ":")
+		l.advance()
+		return true
+	case ';':
+		l.addToken(token.Semicolon, ";")
+		l.advance()
+		return true
+	}
+
+	return false
+}
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+}
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// Tokenize scans input and returns its tokens, ignoring any errors
+func Tokenize(input string) []token.Token {
+	return New(input).Tokenize()
+}
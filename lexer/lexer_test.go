@@ -0,0 +1,154 @@
+package lexer
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/token"
+	"strings"
+	"testing"
+)
+
+// values lists the values of the tokens of src, writing the SEMICOLON
+// the lexer adds at a line end as NL and a doc comment as DOC(text)
+func values(src string) string {
+	var parts []string
+	for _, tok := range Tokenize(src) {
+		switch {
+		case tok.Type == token.Semicolon && tok.Value == "\n":
+			parts = append(parts, "NL")
+		case tok.Type == token.DocComment:
+			parts = append(parts, "DOC("+tok.Value+")")
+		default:
+			parts = append(parts, tok.Value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLineEnds(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"identifier then parenthesis", "b\n(c)\n", "b NL ( c ) NL"},
+		{"call", "b(c)\n", "b ( c ) NL"},
+		{"operator", "ye a = 1 +\n2\n", "ye a = 1 + 2 NL"},
+		{"comparison", "a ==\nb\n", "a == b NL"},
+		{"comma", "f(1,\n2)\n", "f ( 1 , 2 ) NL"},
+		{"inside parentheses", "f(\n1\n)\n", "f ( 1 ) NL"},
+		{"open brace", "agar a {\nb()\n}\n", "agar a { b ( ) NL } NL"},
+		{"newline before brace", "agar a\n{\n}\n", "agar a NL { } NL"},
+		{"newline before ya", "}\nya {\n}\n", "} NL ya { } NL"},
+		{"newline before ya fir", "}\nya fir b {\n}\n", "} NL ya fir b { } NL"},
+		{"bare wapas bhejo", "wapas bhejo\n}\n", "wapas bhejo NL } NL"},
+		{"wapas bhejo with a value", "wapas bhejo\nx\n", "wapas bhejo NL x NL"},
+		{"roko and aage badho", "roko\naage badho\n", "roko NL aage badho NL"},
+		{"string", "\"a\"\n'b'\n", "a NL b NL"},
+		{"explicit semicolons", "a; b\n", "a ; b NL"},
+		{"no newline at the end", "a", "a"},
+		{"blank lines", "a\n\n\nb\n", "a NL b NL"},
+		{"trailing comment", "a // c\nb\n", "a NL b NL"},
+		{"trailing block comment", "a /* c */\nb\n", "a NL b NL"},
+		{"block comment spanning lines", "a /* c\nd */ b\n", "a NL b NL"},
+		{"trailing doc comment", "ye a = 1 /// note\nbol(a)\n", "ye a = 1 NL bol ( a ) NL"},
+		{"trailing block doc comment", "ye a = 1 /** note */\nbol(a)\n", "ye a = 1 NL bol ( a ) NL"},
+		{"doc comment", "/// note\nye a = 1\n", "DOC(note) ye a = 1 NL"},
+		{"block doc comment", "/** note */ ye a = 1\n", "DOC(note) ye a = 1 NL"},
+		{"four slashes", "//// note\nye a = 1\n", "ye a = 1 NL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := values(tt.src); got != tt.want {
+				t.Errorf("tokens of %q:\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPositions(t *testing.T) {
+	tokens := Tokenize("ye a = \"x\ny\"\nbol(a)")
+	want := []string{"1:1", "1:4", "1:6", "1:8", "2:3", "3:1", "3:4", "3:5", "3:6"}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens, want %d", len(tokens), len(want))
+	}
+	for idx, tok := range tokens {
+		if got := fmt.Sprintf("%d:%d", tok.Pos.Line, tok.Pos.Column); got != want[idx] {
+			t.Errorf("token %d %q is at %s, want %s", idx, tok.Value, got, want[idx])
+		}
+	}
+}
+
+func TestUnterminatedBlockComment(t *testing.T) {
+	l := New("a /* b /* c */")
+	l.Tokenize()
+	errs := l.Errors()
+	if len(errs) != 1 || errs[0].Error() != "line 1, column 3: unterminated block comment" {
+		t.Errorf("errors = %v, want the unterminated block comment at 1:3", errs)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"plain", `"a b"`, `STRING "a b"`},
+		{"escaped brace", `"a \{b}"`, `STRING "a {b}"`},
+		{"escaped quote", `"a \"b\""`, `STRING "a \"b\""`},
+		{"template", `"a {b} c"`, `TEMPLATE "a {b} c"`},
+		{"escaped brace in a template", `"\{a} {b}"`, `TEMPLATE "\\{a} {b}"`},
+		{"nested braces", `"{f({a})}"`, `TEMPLATE "{f({a})}"`},
+		{"brace in a string in a placeholder", `"{f('}')}"`, `TEMPLATE "{f('}')}"`},
+		{"other quote", `'{f("}")}'`, `TEMPLATE "{f(\"}\")}"`},
+		{"escape in a nested string", `"{f('\'}')}"`, `TEMPLATE "{f('\\'}')}"`},
+		{"placeholder over two lines", "\"{a +\n1}\" b", `TEMPLATE "{a +\n1}" IDENTIFIER "b"`},
+		// the string's own quote ends it even inside a placeholder
+		{"unterminated placeholder", `bol("{x")`, `IDENTIFIER "bol" PAREN "(" TEMPLATE "{x" PAREN ")"`},
+		{"unterminated placeholder before a line", "bol(\"{x\")\nbol(\"y\")",
+			`IDENTIFIER "bol" PAREN "(" TEMPLATE "{x" PAREN ")" SEMICOLON "\n" IDENTIFIER "bol" PAREN "(" STRING "y" PAREN ")"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parts []string
+			for _, tok := range Tokenize(tt.src) {
+				parts = append(parts, fmt.Sprintf("%s %q", tok.Type, tok.Value))
+			}
+			if got := strings.Join(parts, " "); got != tt.want {
+				t.Errorf("tokens of %q:\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShebang(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"skipped", "#!/usr/bin/env hlang\nbol(1)\n", "bol ( 1 ) NL"},
+		{"with flags", "#!/usr/bin/env -S hlang run --max-steps 100\nbol(1)", "bol ( 1 )"},
+		{"only a shebang", "#!/usr/bin/env hlang", ""},
+		{"windows line end", "#!/usr/bin/env hlang\r\nbol(1)", "bol ( 1 )"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := values(tt.src); got != tt.want {
+				t.Errorf("tokens of %q:\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+
+	l := New("#!/usr/bin/env hlang\nye a = 1")
+	if tok := l.Tokenize()[0]; tok.Pos.Line != 2 || tok.Pos.Column != 1 {
+		t.Errorf("first token after a shebang at %s, want line 2, column 1", tok.Pos)
+	}
+
+	// only the first two characters of the source start a shebang
+	for _, src := range []string{" #!/usr/bin/env hlang", "bol(1)\n#!/usr/bin/env hlang"} {
+		if got := values(src); !strings.HasSuffix(got, "/ usr / bin / env hlang") {
+			t.Errorf("tokens of %q: %s, want the shebang line kept", src, got)
+		}
+	}
+}
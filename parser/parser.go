@@ -0,0 +1,814 @@
+// Package parser builds the syntax tree of a HindiScript program from
+// the tokens of the lexer.
+//
+// Statements end at a ";" or at the end of a line. A syntax error doesn't
+// stop the parser, it skips the rest of the broken statement and goes on,
+// so every error in a program is reported at once.
+package parser
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"github.com/suraj-9849/hindiLang.git/token"
+	"slices"
+	"strings"
+)
+
+// Parse lexes and parses src into a Program. The error is
+// lexer.SyntaxErrors holding every syntax error found.
+func Parse(src string) (*ast.Program, error) {
+	l := lexer.New(src)
+	p := New(l.Tokenize())
+	program := p.ParseProgram()
+	if errs := append(l.Errors(), p.Errors()...); len(errs) > 0 {
+		return nil, lexer.SyntaxErrors(errs)
+	}
+	return program, nil
+}
+
+// Parser holds the state of parsing one token stream, create it with New
+type Parser struct {
+	tokens   []token.Token
+	position int
+	length   int
+	errors   []error
+}
+
+// New creates a parser for the tokens of a program
+func New(tokens []token.Token) *Parser {
+	return &Parser{
+		tokens:   tokens,
+		position: 0,
+		length:   len(tokens),
+	}
+}
+
+func (p *Parser) current() *token.Token {
+	if p.position >= p.length {
+		return nil
+	}
+	return &p.tokens[p.position]
+}
+
+func (p *Parser) peek(offset int) *token.Token {
+	pos := p.position + offset
+	if pos >= p.length {
+		return nil
+	}
+	return &p.tokens[pos]
+}
+
+func (p *Parser) advance() {
+	p.position++
+}
+
+// Errors returns the syntax errors found while parsing
+func (p *Parser) Errors() []error {
+	return p.errors
+}
+
+func (p *Parser) errorf(pos token.Position, format string, args ...interface{}) {
+	// one bad token is reported once, not by every rule that trips on it
+	if n := len(p.errors); n > 0 {
+		if last, ok := p.errors[n-1].(*lexer.SyntaxError); ok && last.Pos == pos {
+			return
+		}
+	}
+	p.errors = append(p.errors, &lexer.SyntaxError{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// pos is the position of the current token, or of the last one at the end
+// of input
+func (p *Parser) pos() token.Position {
+	if tok := p.current(); tok != nil {
+		return tok.Pos
+	}
+	if p.length > 0 {
+		return p.tokens[p.length-1].Pos
+	}
+	return token.Position{Line: 1, Column: 1}
+}
+
+// describeToken names a token for error messages
+func describeToken(tok *token.Token) string {
+	if tok == nil {
+		return "end of input"
+	}
+	if tok.Type == token.Semicolon && tok.Value == "\n" {
+		return "newline"
+	}
+	return fmt.Sprintf("%q", tok.Value)
+}
+
+func (p *Parser) expect(tokenType, tokenValue string) bool {
+	tok := p.current()
+	if tok == nil {
+		return false
+	}
+	if tokenType != "" && tok.Type != tokenType {
+		return false
+	}
+	if tokenValue != "" && tok.Value != tokenValue {
+		return false
+	}
+	return true
+}
+
+func (p *Parser) parseExpression() ast.Node {
+	return p.parseBinaryExpression(0)
+}
+
+func (p *Parser) parseExpressionOrError() ast.Node {
+	expr := p.parseExpression()
+	if expr == nil {
+		p.errorf(p.pos(), "expected an expression but found %s", describeToken(p.current()))
+	}
+	return expr
+}
+
+func (p *Parser) parseBinaryExpression(minPrec int) ast.Node {
+	left := p.parsePrimary()
+	if left == nil {
+		return nil
+	}
+
+	for {
+		tok := p.current()
+		if tok == nil || tok.Type != token.Operator {
+			break
+		}
+
+		// "=" and "..." are operators that don't combine expressions
+		op := tok.Value
+		prec := token.Precedence(op)
+		if prec == 0 || prec < minPrec {
+			break
+		}
+
+		opPos := tok.Pos
+		p.advance()
+		right := p.parseBinaryExpression(prec + 1)
+		if right == nil {
+			p.errorf(p.pos(), "expected an expression after %q but found %s", op, describeToken(p.current()))
+			return nil
+		}
+
+		left = &ast.BinaryExpression{
+			Operator: op,
+			Left:     left,
+			Right:    right,
+			Pos:      opPos,
+		}
+	}
+
+	return left
+}
+
+func (p *Parser) parsePrimary() ast.Node {
+	if p.position >= p.length {
+		return nil
+	}
+
+	tok := p.current()
+
+	// doc comments only mean something before declarations
+	if tok.Type == token.DocComment {
+		p.advance()
+		return p.parsePrimary()
+	}
+
+	if tok.Type == token.Number || tok.Type == token.String {
+		p.advance()
+		return &ast.Literal{Kind: tok.Type, Value: tok.Value, Pos: tok.Pos}
+	}
+
+	if tok.Type == token.Template {
+		p.advance()
+		return p.parseTemplate(tok)
+	}
+
+	if tok.Type == token.Identifier {
+		name := tok.Value
+		p.advance()
+		if p.expect(token.Paren, "(") {
+			p.advance()
+			args := p.parseArguments()
+			if !p.expect(token.Paren, ")") {
+				p.errorf(p.pos(), "expected ) to close the call to %s but found %s", name, describeToken(p.current()))
+				return nil
+			}
+			p.advance()
+			return &ast.FunctionCall{Name: name, Arguments: args, Pos: tok.Pos}
+		}
+
+		return &ast.Identifier{Name: name, Pos: tok.Pos}
+	}
+
+	if tok.Type == token.Paren && tok.Value == "(" {
+		p.advance()
+		expr := p.parseExpressionOrError()
+		if expr == nil {
+			return nil
+		}
+		if !p.expect(token.Paren, ")") {
+			p.errorf(p.pos(), "expected ) but found %s", describeToken(p.current()))
+			return nil
+		}
+		p.advance()
+		return expr
+	}
+
+	return nil
+}
+
+// parseTemplate splits the raw text of a TEMPLATE token into its text
+// parts and placeholders, lexing and parsing each placeholder on its own
+func (p *Parser) parseTemplate(tok *token.Token) ast.Node {
+	start := tok.Pos
+	raw := []rune(tok.Value)
+	template := &ast.TemplateLiteral{Pos: start}
+
+	// the text begins right after the opening quote
+	line, column := start.Line, start.Column+1
+	step := func(r rune) {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	text := []rune{}
+	for idx := 0; idx < len(raw); idx++ {
+		r := raw[idx]
+
+		if r == '\\' && idx+1 < len(raw) {
+			text = append(text, raw[idx+1])
+			step(r)
+			step(raw[idx+1])
+			idx++
+			continue
+		}
+
+		if r != '{' {
+			text = append(text, r)
+			step(r)
+			continue
+		}
+
+		bracePos := token.Position{Line: line, Column: column}
+		end := matchingBrace(raw, idx)
+		if end < 0 {
+			p.errorf(bracePos, "unterminated placeholder in string")
+			break
+		}
+
+		step(r)
+		exprPos := token.Position{Line: line, Column: column}
+		source := raw[idx+1 : end]
+		for _, sr := range source {
+			step(sr)
+		}
+		step(raw[end])
+		idx = end
+
+		template.Quasis = append(template.Quasis, string(text))
+		text = []rune{}
+		template.Expressions = append(template.Expressions, p.parsePlaceholder(string(source), exprPos, bracePos))
+	}
+	template.Quasis = append(template.Quasis, string(text))
+
+	return template
+}
+
+// matchingBrace finds the } closing the { at open, skipping over quoted
+// strings, or returns -1
+func matchingBrace(raw []rune, open int) int {
+	depth := 0
+	for idx := open; idx < len(raw); idx++ {
+		switch raw[idx] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return idx
+			}
+		case '"', '\'':
+			quote := raw[idx]
+			for idx++; idx < len(raw) && raw[idx] != quote; idx++ {
+				if raw[idx] == '\\' {
+					idx++
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func (p *Parser) parsePlaceholder(source string, exprPos, bracePos token.Position) ast.Node {
+	// a placeholder is one expression, so line ends inside it are ignored
+	// just like inside parentheses
+	l := lexer.NewExpression(source, exprPos)
+	sub := New(l.Tokenize())
+	expr := sub.parseExpression()
+	p.errors = append(p.errors, l.Errors()...)
+	p.errors = append(p.errors, sub.errors...)
+
+	if expr == nil {
+		if tok := sub.current(); tok != nil {
+			p.errorf(tok.Pos, "unexpected %q in placeholder", tok.Value)
+		} else if sub.length > 0 {
+			last := sub.tokens[sub.length-1]
+			p.errorf(last.Pos, "incomplete expression after %q in placeholder", last.Value)
+		} else {
+			p.errorf(bracePos, "empty placeholder in string")
+		}
+		return nil
+	}
+	if tok := sub.current(); tok != nil {
+		p.errorf(tok.Pos, "unexpected %q in placeholder", tok.Value)
+	}
+	return expr
+}
+
+func (p *Parser) parseArguments() []ast.Node {
+	args := []ast.Node{}
+
+	for !p.expect(token.Paren, ")") && p.position < p.length {
+		arg := p.parseExpression()
+		if arg == nil {
+			p.errorf(p.pos(), "unexpected %s in argument list", describeToken(p.current()))
+			break
+		}
+		args = append(args, arg)
+
+		if p.expect(token.Comma, ",") {
+			p.advance()
+		} else if !p.expect(token.Paren, ")") {
+			p.errorf(p.pos(), "expected , or ) but found %s", describeToken(p.current()))
+			break
+		}
+	}
+
+	return args
+}
+
+// parseBlock parses "{ ... }" in place on the same token stream, so nested
+// blocks cost nothing extra and errors keep their positions
+func (p *Parser) parseBlock() []ast.Node {
+	// a block may open on the line after its header
+	if p.expect(token.Semicolon, "\n") && p.peek(1) != nil && p.peek(1).Value == "{" {
+		p.advance()
+	}
+	if !p.expect(token.Brace, "{") {
+		p.errorf(p.pos(), "expected { but found %s", describeToken(p.current()))
+		return []ast.Node{}
+	}
+	open := p.pos()
+	p.advance()
+
+	body := p.parseStatements(true)
+
+	if !p.expect(token.Brace, "}") {
+		p.errorf(open, "unclosed {, expected } before %s", describeToken(p.current()))
+		return body
+	}
+	p.advance()
+	return body
+}
+
+func (p *Parser) parseFunctionDeclaration() ast.Node {
+	pos := p.pos()
+	p.advance()
+
+	if !p.expect(token.Identifier, "") {
+		p.errorf(p.pos(), "expected a function name after firseKaro but found %s", describeToken(p.current()))
+		p.skipStatement()
+		return nil
+	}
+
+	name := p.current().Value
+	p.advance()
+
+	params := []ast.Parameter{}
+	if p.expect(token.Paren, "(") {
+		p.advance()
+
+		for !p.expect(token.Paren, ")") && p.position < p.length {
+			param, ok := p.parseParameter(params)
+			if !ok {
+				p.skipStatement()
+				return nil
+			}
+			params = append(params, param)
+
+			if p.expect(token.Comma, ",") {
+				p.advance()
+			} else if !p.expect(token.Paren, ")") {
+				p.errorf(p.pos(), "expected , or ) but found %s", describeToken(p.current()))
+				p.skipStatement()
+				return nil
+			}
+		}
+
+		if !p.expect(token.Paren, ")") {
+			p.errorf(p.pos(), "expected ) after the parameters of %s but found %s", name, describeToken(p.current()))
+			return nil
+		}
+		p.advance()
+	}
+
+	returnType := ""
+	if p.expect(token.Colon, ":") {
+		p.advance()
+
+		var ok bool
+		if returnType, ok = p.parseType(); !ok {
+			p.skipStatement()
+			return nil
+		}
+	}
+
+	body := p.parseBlock()
+
+	return &ast.FunctionDeclaration{
+		Name:       name,
+		Parameters: params,
+		ReturnType: returnType,
+		Body:       body,
+		Pos:        pos,
+	}
+}
+
+// parseParameter parses "naam", "naam = default" or "...naam". Parameters
+// with defaults come after the ones without, and a rest parameter is last.
+func (p *Parser) parseParameter(before []ast.Parameter) (ast.Parameter, bool) {
+	param := ast.Parameter{Pos: p.pos()}
+	if p.expect(token.Operator, "...") {
+		param.Rest = true
+		p.advance()
+	}
+	if !p.expect(token.Identifier, "") {
+		p.errorf(p.pos(), "expected a parameter name but found %s", describeToken(p.current()))
+		return param, false
+	}
+	param.Name = p.current().Value
+	p.advance()
+	if p.expect(token.Colon, ":") {
+		p.advance()
+		var ok bool
+		if param.Type, ok = p.parseType(); !ok {
+			return param, false
+		}
+	}
+
+	for _, prev := range before {
+		if prev.Name == param.Name {
+			p.errorf(param.Pos, "duplicate parameter %s", param.Name)
+			return param, false
+		}
+	}
+	if n := len(before); n > 0 && before[n-1].Rest {
+		p.errorf(before[n-1].Pos, "rest parameter ...%s must be the last one", before[n-1].Name)
+		return param, false
+	}
+
+	if p.expect(token.Operator, "=") {
+		if param.Rest {
+			p.errorf(p.pos(), "rest parameter ...%s cannot have a default", param.Name)
+			return param, false
+		}
+		p.advance()
+		param.Default = p.parseExpressionOrError()
+		if param.Default == nil {
+			return param, false
+		}
+	} else if n := len(before); !param.Rest && n > 0 && before[n-1].Default != nil {
+		p.errorf(param.Pos, "parameter %s needs a default, it follows one that has a default", param.Name)
+		return param, false
+	}
+	return param, true
+}
+
+func (p *Parser) parseDeclaration() ast.Node {
+	pos := p.pos()
+	p.advance()
+
+	if !p.expect(token.Identifier, "") {
+		p.errorf(p.pos(), "expected a name after ye but found %s", describeToken(p.current()))
+		return nil
+	}
+
+	name := p.current().Value
+	p.advance()
+	typ := ""
+	if p.expect(token.Colon, ":") {
+		p.advance()
+		var ok bool
+		if typ, ok = p.parseType(); !ok {
+			p.skipStatement()
+			return nil
+		}
+	}
+	if !p.expect(token.Operator, "=") {
+		p.errorf(p.pos(), "expected = after %s but found %s", name, describeToken(p.current()))
+		return nil
+	}
+	p.advance()
+
+	value := p.parseExpressionOrError()
+
+	return &ast.Declaration{Name: name, Type: typ, Value: value, Pos: pos}
+}
+
+// parseType parses the type name after the ":" of an annotation
+func (p *Parser) parseType() (string, bool) {
+	tok := p.current()
+	if tok == nil || tok.Type != token.Identifier {
+		p.errorf(p.pos(), "expected a type after : but found %s", describeToken(tok))
+		return "", false
+	}
+	p.advance()
+	if !slices.Contains(ast.TypeNames, tok.Value) {
+		p.errorf(tok.Pos, "unknown type %s, expected one of %s", tok.Value, strings.Join(ast.TypeNames, ", "))
+		return "", false
+	}
+	return tok.Value, true
+}
+
+func (p *Parser) parseAssignment() ast.Node {
+	pos := p.pos()
+	name := p.current().Value
+	p.advance()
+	if !p.expect(token.Operator, "=") {
+		return nil
+	}
+	p.advance()
+
+	value := p.parseExpressionOrError()
+
+	return &ast.Assignment{Name: name, Value: value, Pos: pos}
+}
+
+func (p *Parser) parseIfStatement() ast.Node {
+	pos := p.pos()
+	p.advance()
+
+	condition := p.parseCondition("agar")
+	consequent := p.parseBlock()
+
+	elseIfs := []ast.ElseIfStatement{}
+	alternate := []ast.Node{}
+	for p.position < p.length {
+		// "}" and "ya" may be on different lines
+		if p.expect(token.Semicolon, "\n") && p.peek(1) != nil && p.peek(1).Type == token.Keyword &&
+			(p.peek(1).Value == "ya" || p.peek(1).Value == "ya fir") {
+			p.advance()
+		}
+		tok := p.current()
+
+		if tok != nil && tok.Type == token.Keyword && tok.Value == "ya fir" {
+			elseIfPos := tok.Pos
+			p.advance()
+			elseIfCond := p.parseCondition("ya fir")
+			elseIfConsequent := p.parseBlock()
+
+			elseIfs = append(elseIfs, ast.ElseIfStatement{
+				Condition:  elseIfCond,
+				Consequent: elseIfConsequent,
+				Pos:        elseIfPos,
+			})
+		} else if tok != nil && tok.Type == token.Keyword && tok.Value == "ya" {
+			p.advance()
+			alternate = p.parseBlock()
+			break
+		} else {
+			break
+		}
+	}
+
+	return &ast.IfStatement{
+		Condition:  condition,
+		Consequent: consequent,
+		ElseIfs:    elseIfs,
+		Alternate:  alternate,
+		Pos:        pos,
+	}
+}
+
+// parseCondition parses the condition following agar, ya fir or jabtak
+func (p *Parser) parseCondition(keyword string) ast.Node {
+	condition := p.parseExpression()
+	if condition == nil {
+		p.errorf(p.pos(), "expected a condition after %s but found %s", keyword, describeToken(p.current()))
+	}
+	return condition
+}
+
+func (p *Parser) parseWhileLoop() ast.Node {
+	pos := p.pos()
+	p.advance()
+
+	condition := p.parseCondition("jabtak")
+	body := p.parseBlock()
+
+	return &ast.WhileLoop{
+		Condition: condition,
+		Body:      body,
+		Pos:       pos,
+	}
+}
+
+func (p *Parser) parseRepeatLoop() ast.Node {
+	pos := p.pos()
+	p.advance()
+	body := p.parseBlock()
+
+	return &ast.RepeatLoop{
+		Body: body,
+		Pos:  pos,
+	}
+}
+
+func (p *Parser) parseBreakStatement() ast.Node {
+	pos := p.pos()
+	p.advance()
+	return &ast.BreakStatement{Pos: pos}
+}
+
+func (p *Parser) parseContinueStatement() ast.Node {
+	pos := p.pos()
+	p.advance()
+	return &ast.ContinueStatement{Pos: pos}
+}
+
+func (p *Parser) parseReturnStatement() ast.Node {
+	pos := p.pos()
+	p.advance()
+
+	var value ast.Node
+	tok := p.current()
+
+	if tok != nil && tok.Type != token.Semicolon && !(tok.Type == token.Brace && tok.Value == "}") {
+		value = p.parseExpression()
+	}
+
+	return &ast.ReturnStatement{
+		Value: value,
+		Pos:   pos,
+	}
+}
+
+// parseDocComment joins consecutive /// lines into one doc string
+func (p *Parser) parseDocComment(doc string) string {
+	text := p.current().Value
+	p.advance()
+	if doc == "" {
+		return text
+	}
+	return doc + "\n" + text
+}
+
+// ParseProgram parses the whole token stream. Syntax errors are skipped
+// over and reported by Errors.
+func (p *Parser) ParseProgram() *ast.Program {
+	return &ast.Program{Body: p.parseStatements(false)}
+}
+
+// parseStatements parses statements until the end of input, or until the
+// "}" closing the current block when inBlock is set.
+//
+// Statements end at a ";" or at the end of a line, the lexer turns those
+// line ends into SEMICOLON tokens with the value "\n". Two statements on
+// the same line need a ";" between them.
+func (p *Parser) parseStatements(inBlock bool) []ast.Node {
+	body := []ast.Node{}
+
+	doc := ""
+	for p.position < p.length {
+		tok := p.current()
+
+		if tok.Type == token.Semicolon {
+			p.advance()
+			continue
+		}
+
+		if tok.Type == token.Brace && tok.Value == "}" {
+			if inBlock {
+				break
+			}
+			p.errorf(tok.Pos, "unexpected }")
+			p.advance()
+			continue
+		}
+
+		if tok.Type == token.DocComment {
+			doc = p.parseDocComment(doc)
+			continue
+		}
+
+		node := p.parseStatement()
+
+		switch n := node.(type) {
+		case *ast.FunctionDeclaration:
+			n.Doc = doc
+		case *ast.Declaration:
+			n.Doc = doc
+		}
+		doc = ""
+
+		if node != nil {
+			body = append(body, node)
+		}
+		p.endStatement()
+	}
+
+	return body
+}
+
+func (p *Parser) parseStatement() ast.Node {
+	tok := p.current()
+
+	if tok.Type == token.Keyword {
+		switch tok.Value {
+		case "firseKaro":
+			return p.parseFunctionDeclaration()
+		case "ye":
+			return p.parseDeclaration()
+		case "agar":
+			return p.parseIfStatement()
+		case "jabtak":
+			return p.parseWhileLoop()
+		case "dohraye":
+			return p.parseRepeatLoop()
+		case "roko":
+			return p.parseBreakStatement()
+		case "aage badho":
+			return p.parseContinueStatement()
+		case "wapas bhejo":
+			return p.parseReturnStatement()
+		default:
+			p.errorf(tok.Pos, "unexpected keyword %q", tok.Value)
+			p.skipStatement()
+			return nil
+		}
+	}
+
+	if tok.Type == token.Identifier && p.peek(1) != nil && p.peek(1).Type == token.Operator && p.peek(1).Value == "=" {
+		return p.parseAssignment()
+	}
+
+	reported := len(p.errors)
+	node := p.parseExpression()
+	if node == nil {
+		// a broken expression has already said what is wrong with it
+		if len(p.errors) == reported {
+			p.errorf(tok.Pos, "unexpected %s", describeToken(tok))
+		}
+		p.skipStatement()
+	}
+	return node
+}
+
+// endStatement requires a statement to be followed by a separator or the
+// end of its block
+func (p *Parser) endStatement() {
+	tok := p.current()
+	if tok == nil {
+		return
+	}
+	if tok.Type == token.Semicolon {
+		p.advance()
+		return
+	}
+	if tok.Type == token.Brace && tok.Value == "}" {
+		return
+	}
+	p.errorf(tok.Pos, "expected ; or newline before %s", describeToken(tok))
+	p.skipStatement()
+}
+
+// skipStatement moves past the rest of a broken statement so one mistake
+// doesn't produce a pile of errors
+func (p *Parser) skipStatement() {
+	depth := 0
+	for p.position < p.length {
+		tok := p.current()
+		if tok.Type == token.Semicolon && depth == 0 {
+			return
+		}
+		if tok.Type == token.Brace {
+			if tok.Value == "{" {
+				depth++
+			} else if depth == 0 {
+				return
+			} else {
+				depth--
+			}
+		}
+		p.advance()
+	}
+}
@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/suraj-9849/hindiLang.git/ast"
+	"github.com/suraj-9849/hindiLang.git/lexer"
+	"strings"
+	"testing"
+)
+
+// show writes a node as a short s-expression, enough to tell apart the
+// ways a source can be split into statements
+func show(node ast.Node) string {
+	switch n := node.(type) {
+	case nil:
+		return "nil"
+	case *ast.Program:
+		return showBlock(n.Body)
+	case *ast.Declaration:
+		doc := ""
+		if n.Doc != "" {
+			doc = fmt.Sprintf(" doc=%q", n.Doc)
+		}
+		return fmt.Sprintf("(ye %s %s%s)", n.Name, show(n.Value), doc)
+	case *ast.Assignment:
+		return fmt.Sprintf("(= %s %s)", n.Name, show(n.Value))
+	case *ast.Identifier:
+		return n.Name
+	case *ast.Literal:
+		if n.IsNumber() {
+			return n.Value
+		}
+		return fmt.Sprintf("%q", n.Value)
+	case *ast.TemplateLiteral:
+		parts := []string{fmt.Sprintf("%q", n.Quasis[0])}
+		for idx, expr := range n.Expressions {
+			parts = append(parts, show(expr), fmt.Sprintf("%q", n.Quasis[idx+1]))
+		}
+		return fmt.Sprintf("(template %s)", strings.Join(parts, " "))
+	case *ast.BinaryExpression:
+		return fmt.Sprintf("(%s %s %s)", n.Operator, show(n.Left), show(n.Right))
+	case *ast.FunctionCall:
+		args := make([]string, len(n.Arguments))
+		for idx, arg := range n.Arguments {
+			args[idx] = show(arg)
+		}
+		return fmt.Sprintf("%s(%s)", n.Name, strings.Join(args, " "))
+	case *ast.FunctionDeclaration:
+		params := make([]string, len(n.Parameters))
+		for idx, param := range n.Parameters {
+			params[idx] = param.Name
+		}
+		return fmt.Sprintf("(firseKaro %s(%s) %s)", n.Name, strings.Join(params, " "), showBlock(n.Body))
+	case *ast.IfStatement:
+		s := fmt.Sprintf("(agar %s %s", show(n.Condition), showBlock(n.Consequent))
+		for _, elseIf := range n.ElseIfs {
+			s += fmt.Sprintf(" ya fir %s %s", show(elseIf.Condition), showBlock(elseIf.Consequent))
+		}
+		if len(n.Alternate) > 0 {
+			s += " ya " + showBlock(n.Alternate)
+		}
+		return s + ")"
+	case *ast.WhileLoop:
+		return fmt.Sprintf("(jabtak %s %s)", show(n.Condition), showBlock(n.Body))
+	case *ast.RepeatLoop:
+		return fmt.Sprintf("(dohraye %s)", showBlock(n.Body))
+	case *ast.BreakStatement:
+		return "(roko)"
+	case *ast.ContinueStatement:
+		return "(aage badho)"
+	case *ast.ReturnStatement:
+		if n.Value == nil {
+			return "(wapas bhejo)"
+		}
+		return fmt.Sprintf("(wapas bhejo %s)", show(n.Value))
+	default:
+		return n.NodeType()
+	}
+}
+
+func showBlock(nodes []ast.Node) string {
+	parts := make([]string, len(nodes))
+	for idx, node := range nodes {
+		parts[idx] = show(node)
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+func TestStatementEnds(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"identifier then parenthesis", "b\n(c)\n", "{b c}"},
+		{"call", "b(c)\n", "{b(c)}"},
+		{"line ending in an operator", "ye a = 1 +\n2\n", "{(ye a (+ 1 2))}"},
+		{"line ending in &&", "ye a = b &&\nc\n", "{(ye a (&& b c))}"},
+		{"line ending in a comma", "f(1,\n2)\n", "{f(1 2)}"},
+		{"parameters on several lines", "firseKaro f(a,\nb\n) {\n}\n", "{(firseKaro f(a b) {})}"},
+		{"newline before {", "agar a\n{\nb()\n}\n", "{(agar a {b()})}"},
+		{"newline before { of a function", "firseKaro f()\n{\n}\n", "{(firseKaro f() {})}"},
+		{"newline before { of a loop", "jabtak a\n{\n}\ndohraye\n{\n}\n", "{(jabtak a {}) (dohraye {})}"},
+		{"newline before ya", "agar a {\nb()\n}\nya {\nc()\n}\n", "{(agar a {b()} ya {c()})}"},
+		{"newline before ya fir", "agar a {\n}\nya fir b {\n}\nya\n{\nc()\n}\n", "{(agar a {} ya fir b {} ya {c()})}"},
+		{"bare wapas bhejo", "firseKaro f() {\nwapas bhejo\n}\n", "{(firseKaro f() {(wapas bhejo)})}"},
+		{"bare wapas bhejo before a statement", "firseKaro f() {\nwapas bhejo\nx\n}\n", "{(firseKaro f() {(wapas bhejo) x})}"},
+		{"wapas bhejo with a value", "firseKaro f() {\nwapas bhejo x\n}\n", "{(firseKaro f() {(wapas bhejo x)})}"},
+		{"semicolons", "a = 1; b = 2\n", "{(= a 1) (= b 2)}"},
+		{"trailing comment", "ye a = 1 // c\nbol(a)\n", "{(ye a 1) bol(a)}"},
+		{"trailing block comment", "ye a = 1 /* c */\nbol(a)\n", "{(ye a 1) bol(a)}"},
+		{"trailing doc comment", "ye a = 1 /// note\nbol(a)\n", "{(ye a 1) bol(a)}"},
+		{"trailing doc comment before a declaration", "ye a = 1 /// note\nye b = 2\n", "{(ye a 1) (ye b 2)}"},
+		{"doc comment", "/// one\n/// two\nye a = 1\n", `{(ye a 1 doc="one\ntwo")}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("parsing %q: %v", tt.src, err)
+			}
+			if got := show(program); got != tt.want {
+				t.Errorf("parsing %q:\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementEndErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"two statements on a line", "ye a = 1 ye b = 2", `line 1, column 10: expected ; or newline before "ye"`},
+		{"operator at the start of a line", "ye a = 1\n+ 2\n", "line 2, column 1: "},
+		{"unclosed block", "agar a {\nb()\n", "line 1, column 8: unclosed {"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parsing %q: error = %v, want one holding %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"placeholders", `bol("a {b} c {d + 1}")`, `{bol((template "a " b " c " (+ d 1) ""))}`},
+		{"only a placeholder", `bol("{b}")`, `{bol((template "" b ""))}`},
+		{"escaped braces", `bol("\{a} {b} \{c}")`, `{bol((template "{a} " b " {c}"))}`},
+		{"escaped quote", `bol("\"{b}\"")`, `{bol((template "\"" b "\""))}`},
+		{"call with a string", `bol("{lambai('a}b')}")`, `{bol((template "" lambai("a}b") ""))}`},
+		{"template in a placeholder", `bol("{f('{x}')}")`, `{bol((template "" f((template "" x "")) ""))}`},
+		{"nested braces", `bol("{f(1, 2)} {a}")`, `{bol((template "" f(1 2) " " a ""))}`},
+		{"placeholder over two lines", "bol(\"{a +\n1}\")", `{bol((template "" (+ a 1) ""))}`},
+		{"no placeholder", `bol("a \{b}")`, `{bol("a {b}")}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("parsing %q: %v", tt.src, err)
+			}
+			if got := show(program); got != tt.want {
+				t.Errorf("parsing %q:\n got %s\nwant %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplatePositions(t *testing.T) {
+	program, err := Parse("ye a = \"x {b} \\{ {c +\n  d}\"")
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := program.Body[0].(*ast.Declaration).Value.(*ast.TemplateLiteral)
+	var got []string
+	ast.Inspect(template, func(node ast.Node) bool {
+		if node != nil {
+			got = append(got, fmt.Sprintf("%s %s", show(node), ast.Start(node)))
+		}
+		return true
+	})
+	want := []string{
+		`(template "x " b " { " (+ c d) "") line 1, column 8`,
+		"b line 1, column 12",
+		"(+ c d) line 1, column 19",
+		"c line 1, column 19",
+		"d line 2, column 3",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("positions:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string // every error, one per line
+	}{
+		{"unterminated placeholder", `bol("{x")`, "line 1, column 6: unterminated placeholder in string"},
+		{"unterminated placeholder before a line", "bol(\"{x\")\nbol(y)", "line 1, column 6: unterminated placeholder in string"},
+		{"empty placeholder", `bol("a {}")`, "line 1, column 8: empty placeholder in string"},
+		{"incomplete expression", `bol("a {b +}")`, `line 1, column 11: expected an expression after "+" but found end of input`},
+		{"two expressions", `bol("a {b c}")`, `line 1, column 11: unexpected "c" in placeholder`},
+		{"unexpected token", `bol("{)}")`, `line 1, column 7: unexpected ")" in placeholder`},
+		{"on a later line", "bol(\"a\n  {b +}\")", `line 2, column 6: expected an expression after "+" but found end of input`},
+		{"inside a placeholder over two lines", "bol(\"{a +\n  b c}\")", `line 2, column 5: unexpected "c" in placeholder`},
+		{"after a tab and escapes", "bol(\"\\{\t{)}\")", `line 1, column 10: unexpected ")" in placeholder`},
+		{"in a nested template", `bol("{f('{b +}')}")`, `line 1, column 13: expected an expression after "+" but found end of input`},
+		{"unterminated string in a placeholder", `bol("{f('a)}")`, "line 1, column 6: unterminated placeholder in string"},
+		{"two placeholders", `bol("{} {b c}")`, "line 1, column 6: empty placeholder in string\n" +
+			`line 1, column 12: unexpected "c" in placeholder`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("parsing %q:\n got %v\nwant %s", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+// nestedSource is depth agar blocks inside each other
+func nestedSource(depth int) string {
+	var sb strings.Builder
+	sb.WriteString("ye a = 1\n")
+	for range depth {
+		sb.WriteString("agar a < 2 {\n")
+	}
+	sb.WriteString("bol(a)\n")
+	for range depth {
+		sb.WriteString("} ya {\nbol(a)\n}\n")
+	}
+	return sb.String()
+}
+
+// parenSource is an expression in depth parentheses
+func parenSource(depth int) string {
+	return "ye a = " + strings.Repeat("(1 + ", depth) + "1" + strings.Repeat(")", depth) + "\n"
+}
+
+// flatSource is a program of about lines lines at the top level
+func flatSource(lines int) string {
+	var sb strings.Builder
+	for idx := 0; idx < lines/5; idx++ {
+		fmt.Fprintf(&sb, "firseKaro f%d(a, b = %d) {\n", idx, idx)
+		fmt.Fprintf(&sb, "\tye x = a * b + %d\n", idx)
+		sb.WriteString("\tagar x > 10 { wapas bhejo \"bada {x}\" }\n")
+		sb.WriteString("\twapas bhejo x\n")
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
+
+// benchmarkParse parses the sources of growing sizes; ns/token staying
+// the same as the size grows shows parsing takes linear time
+func benchmarkParse(b *testing.B, name string, sizes []int, source func(int) string) {
+	for _, size := range sizes {
+		src := source(size)
+		if _, err := Parse(src); err != nil {
+			b.Fatalf("%s %d does not parse: %v", name, size, err)
+		}
+		tokens := len(lexer.Tokenize(src))
+		b.Run(fmt.Sprintf("%s=%d", name, size), func(b *testing.B) {
+			b.SetBytes(int64(len(src)))
+			for b.Loop() {
+				Parse(src)
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*tokens), "ns/token")
+		})
+	}
+}
+
+func BenchmarkParseNested(b *testing.B) {
+	benchmarkParse(b, "depth", []int{10, 100, 1000, 10000}, nestedSource)
+}
+
+func BenchmarkParseNestedParens(b *testing.B) {
+	benchmarkParse(b, "depth", []int{10, 100, 1000, 10000}, parenSource)
+}
+
+func BenchmarkParseFlat(b *testing.B) {
+	benchmarkParse(b, "lines", []int{100, 1000, 10000, 100000}, flatSource)
+}
@@ -0,0 +1,101 @@
+// Package token defines the tokens of HindiScript and the positions that
+// the lexer, the parser and the syntax tree share.
+package token
+
+import "fmt"
+
+// Types of tokens, the Type of a Token
+const (
+	Keyword    = "KEYWORD"
+	Identifier = "IDENTIFIER"
+	Number     = "NUMBER"
+	String     = "STRING"
+	Template   = "TEMPLATE"
+	Operator   = "OPERATOR"
+	Paren      = "PAREN"
+	Brace      = "BRACE"
+	Comma      = "COMMA"
+	Colon      = "COLON"
+	Semicolon  = "SEMICOLON"
+	DocComment = "DOC_COMMENT"
+)
+
+// Token is one token of the source. Value is its text, for a STRING the
+// text with escapes resolved and for a TEMPLATE the raw text between the
+// quotes. Raw is the string as written, quotes included, and only set for
+// STRING and TEMPLATE tokens. A SEMICOLON the lexer added at a line end
+// has the Value "\n".
+type Token struct {
+	Type  string
+	Value string
+	Raw   string
+	Pos   Position
+}
+
+// Position is a 1-based line and column in the source
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// Before reports whether p comes before q in the source
+func (p Position) Before(q Position) bool {
+	return p.Line < q.Line || (p.Line == q.Line && p.Column < q.Column)
+}
+
+// Comment is a comment as written in the source, markers included. The
+// parser never sees them, the lexer keeps them aside so tools like the
+// formatter can put them back.
+type Comment struct {
+	Text string
+	Pos  Position
+}
+
+var keywords = map[string]bool{
+	"ye":          true, // var/const
+	"agar":        true, // if
+	"ya":          true,
+	"fir":         true,
+	"ya fir":      true, // else if
+	"firseKaro":   true, //func
+	"jabtak":      true, // while
+	"dohraye":     true, // repeat
+	"roko":        true, // break
+	"aage badho":  true, // continue
+	"aage":        true,
+	"badho":       true,
+	"wapas bhejo": true, // return
+	"wapas":       true,
+	"bhejo":       true,
+}
+
+// IsKeyword reports whether word is a keyword, including the two word
+// keywords like "ya fir" and their single words
+func IsKeyword(word string) bool {
+	return keywords[word]
+}
+
+// Precedence is how tightly a binary operator binds, higher binds
+// tighter. Operators that don't combine expressions, like "=", have 0.
+func Precedence(op string) int {
+	switch op {
+	case "||":
+		return 1
+	case "&&":
+		return 2
+	case "==", "!=":
+		return 3
+	case "<", ">", "<=", ">=":
+		return 4
+	case "+", "-":
+		return 5
+	case "*", "/", "%":
+		return 6
+	default:
+		return 0
+	}
+}